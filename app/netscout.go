@@ -4,9 +4,13 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"text/tabwriter"
 	"time"
 
 	"github.com/caio-ishikawa/netscout/osint"
@@ -21,18 +25,40 @@ const (
 	reset  = "\033[0m"
 )
 
+// Timeout of a single DNS query
+const dnsTimeout = 5 * time.Second
+
 type NetScout struct {
-	mutex      sync.Mutex
-	outputFile *os.File
-	settings   Settings
-	Extensions []string
+	mutex        sync.Mutex
+	outputFile   *os.File
+	settings     Settings
+	Extensions   []string
+	technologies map[string][]string
+	favicons     map[string][]string
+	headerAudits []osint.HeaderAudit
+	files        []url.URL
+	fileMap      map[string]struct{}
+	collectors   sync.WaitGroup
+	subdomains   []string
+	subdomainMap map[string]struct{}
+	resolver     *osint.SubdomainResolver
+	dnsRecords   []osint.HostRecords
+	ipRanges     []string
+	addresses    []string
+	addressMap   map[string]struct{}
+	ptrRecords   []osint.PtrRecord
 }
 
 func NewApp(settings Settings) (NetScout, error) {
 	return NetScout{
-		outputFile: nil,
-		settings:   settings,
-		Extensions: []string{},
+		outputFile:   nil,
+		settings:     settings,
+		Extensions:   []string{},
+		technologies: map[string][]string{},
+		favicons:     map[string][]string{},
+		fileMap:      map[string]struct{}{},
+		subdomainMap: map[string]struct{}{},
+		addressMap:   map[string]struct{}{},
 	}, nil
 }
 
@@ -43,6 +69,9 @@ func (ns *NetScout) Scan() {
 
 	// initiate communication channels
 	comms := shared.NewCommsChannels()
+
+	// released by the comms handler once it has received the end of the crawl
+	ns.collectors.Add(1)
 	go ns.handleComms(comms)
 
 	var wg sync.WaitGroup
@@ -58,6 +87,10 @@ func (ns *NetScout) Scan() {
 	}
 
 	ns.outputUrls(subdomains, shared.Axfr)
+	ns.collectSubdomains(subdomains)
+
+	// dnssec zone walk
+	ns.walkZone()
 
 	// binary edge subdomain query
 	binaryEdgeRes, err := ns.getBinaryEdgeSubdomains()
@@ -66,10 +99,29 @@ func (ns *NetScout) Scan() {
 	}
 
 	ns.outputUrls(binaryEdgeRes, shared.BinaryEdge)
+	ns.collectSubdomains(binaryEdgeRes)
+
+	// dns subdomain brute force
+	ns.bruteForceSubdomains()
+
+	// spf, dmarc and dkim records
+	ns.analyzeMailRecords()
 
 	// crawling happens concurrently, and it updates the state as it finds URLs
 	toCrawl := []url.URL{ns.settings.SeedUrl}
-	ns.crawl(toCrawl, comms)
+	crawler := ns.crawl(toCrawl, comms)
+
+	// permutations of the subdomains found so far
+	ns.permuteSubdomains()
+
+	// dns records of the seed's domain and every subdomain found so far
+	ns.enumerateDnsRecords()
+
+	// ptr records of the addresses found so far and their neighbors
+	ns.sweepReverseDns()
+
+	// dangling CNAMEs of the subdomains found so far
+	ns.detectTakeovers()
 
 	// google dork
 	filetypeLinks, err := ns.getFiletypeResults()
@@ -79,8 +131,27 @@ func (ns *NetScout) Scan() {
 
 	ns.outputUrls(filetypeLinks, shared.Serp)
 
+	for _, link := range filetypeLinks {
+		ns.collectFile(link)
+	}
+
+	// file types of the crawled URLs are collected concurrently, so they must all be known before probing
+	ns.collectors.Wait()
+
+	// backup and variant files of the files found by the crawler and the google dork
+	ns.probeBackups(crawler)
+
+	// metadata of the documents and images found by the crawler and the google dork
+	ns.analyzeDocuments(crawler)
+
 	// wait for goroutines to finish
 	wg.Wait()
+
+	ns.displayTechnologySummary()
+	ns.displayFaviconSummary()
+	ns.displayHeaderAuditSummary()
+	ns.displayDnsSummary()
+	ns.displayPtrSummary()
 }
 
 func (ns *NetScout) createOutputFile(name string) {
@@ -124,17 +195,56 @@ func (ns *NetScout) attemptAXFR() ([]url.URL, error) {
 
 	ns.displaySuccess("Attempting AXFR")
 
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		return []url.URL{}, err
+	}
+
 	domain := shared.RemoveScheme(ns.settings.SeedUrl)
-	subdomains, errs := osint.ZoneTransfer(domain)
-	if len(errs) > 0 {
-		ns.outputWarnings(errs)
+	timeout := time.Duration(ns.settings.AxfrTimeout) * time.Second
+	records, outcomes, err := resolver.ZoneTransfer(domain, timeout)
+	if err != nil {
+		return []url.URL{}, err
 	}
 
-	if len(subdomains) == 0 {
+	// every name server that allowed the transfer leaked the whole zone
+	for _, outcome := range outcomes {
+		if outcome.Err != nil {
+			ns.displayWarning(fmt.Sprintf("AXFR failed against %s: %s", outcome.NameServer, outcome.Err))
+			continue
+		}
+
+		ns.manageFindingChan(shared.Finding{
+			Type:   shared.ZoneTransferLeak,
+			Value:  fmt.Sprintf("%s transferred the %s zone (%d records)", outcome.NameServer, domain, outcome.Records),
+			Url:    ns.settings.SeedUrl,
+			Source: shared.Axfr,
+		})
+	}
+
+	if len(records) == 0 {
 		return []url.URL{}, fmt.Errorf("AFXR yielded no results")
 	}
 
-	return subdomains, nil
+	if ns.settings.ZoneFile != "" {
+		ns.exportZoneFile(domain, records)
+	}
+
+	return osint.ZoneHosts(records), nil
+}
+
+// Writes the transferred records to the zone file as an RFC 1035 master file
+func (ns *NetScout) exportZoneFile(domain string, records []osint.ZoneRecord) {
+	file, err := os.Create(ns.settings.ZoneFile)
+	if err != nil {
+		ns.displayWarning("failed to create zone file - continuing scan")
+		return
+	}
+	defer file.Close()
+
+	if err := osint.WriteZoneFile(file, domain, records); err != nil {
+		ns.displayWarning("failed to write zone file - continuing scan")
+	}
 }
 
 func (ns *NetScout) getBinaryEdgeSubdomains() ([]url.URL, error) {
@@ -173,7 +283,441 @@ func (ns *NetScout) getBinaryEdgeSubdomains() ([]url.URL, error) {
 	return output, nil
 }
 
-func (ns *NetScout) crawl(toCrawl []url.URL, comms shared.CommsChannels) {
+// Enumerates the seed's zone through its NSEC or NSEC3 records. NSEC3 hashes are cracked with the subdomain
+// wordlist and the permutation words.
+func (ns *NetScout) walkZone() []url.URL {
+	if ns.settings.SkipZoneWalk {
+		return []url.URL{}
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping zone walk")
+		return []url.URL{}
+	}
+
+	words := append([]string{}, osint.PermutationWords...)
+	if ns.settings.DnsWordlist != "" {
+		wordlist, err := osint.LoadWordlist(ns.settings.DnsWordlist)
+		if err != nil {
+			ns.displayWarning("failed to load subdomain wordlist - cracking NSEC3 hashes with built-in words")
+		}
+
+		words = append(words, wordlist...)
+	}
+
+	ns.displaySuccess("Walking DNSSEC zone")
+
+	domain := ns.scopeDomain()
+	result, err := resolver.WalkZone(domain, words)
+	if err != nil {
+		ns.displayWarning("failed to walk the zone: " + err.Error() + " - continuing scan")
+	}
+
+	if result.Denial == "" {
+		return []url.URL{}
+	}
+
+	if result.Denial == osint.DenialNsec3 {
+		ns.displayWarning(fmt.Sprintf(
+			"%s uses NSEC3 (%d iterations, salt %q) - cracked %d of %d hashes",
+			domain, result.Params.Iterations, result.Params.Salt, len(result.Names), len(result.Hashes),
+		))
+	} else if !result.Complete {
+		ns.displayWarning("NSEC chain of " + domain + " was only partially walked")
+	}
+
+	var urls []url.URL
+	for _, name := range result.Names {
+		urls = append(urls, url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: name})
+	}
+
+	ns.outputUrls(urls, shared.ZoneWalk)
+	ns.collectSubdomains(urls)
+
+	return urls
+}
+
+func (ns *NetScout) bruteForceSubdomains() []osint.ResolvedHost {
+	if ns.settings.DnsWordlist == "" {
+		return []osint.ResolvedHost{}
+	}
+
+	words, err := osint.LoadWordlist(ns.settings.DnsWordlist)
+	if err != nil {
+		ns.displayWarning("failed to load subdomain wordlist - skipping subdomain brute force")
+		return []osint.ResolvedHost{}
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping subdomain brute force")
+		return []osint.ResolvedHost{}
+	}
+
+	ns.displaySuccess("Brute forcing subdomains")
+
+	domain := ns.scopeDomain()
+	if resolver.IsWildcardDomain(domain) {
+		ns.displayWarning("wildcard DNS detected for " + domain + " - filtering matching answers")
+	}
+
+	hosts := resolver.BruteForce(domain, words)
+	ns.outputResolvedHosts(hosts, shared.DnsBruteForce)
+
+	return hosts
+}
+
+// Follows the SPF, DMARC and DKIM records of the seed's domain. Related domains and IP ranges are reported as leads,
+// and the ones in scope are kept for the modules that follow (e.g. permutations and DNS record enumeration).
+func (ns *NetScout) analyzeMailRecords() {
+	if ns.settings.SkipMailRecords {
+		return
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping mail record analysis")
+		return
+	}
+
+	ns.displaySuccess("Analyzing SPF, DMARC and DKIM records")
+
+	domain := ns.scopeDomain()
+	policy := resolver.AnalyzeMail(domain)
+	seedUrl := url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: domain}
+
+	for _, record := range policy.Records {
+		ns.writeFinding(shared.Finding{
+			Type:   shared.DnsRecord,
+			Value:  record.String(),
+			Url:    seedUrl,
+			Source: shared.MailRecords,
+		})
+	}
+
+	for _, lead := range policy.Domains {
+		if !ns.inDomainScope(lead.Value) {
+			ns.manageFindingChan(shared.Finding{
+				Type:   shared.RelatedHost,
+				Value:  fmt.Sprintf("%s (%s in %s)", lead.Value, lead.Mechanism, lead.Origin),
+				Url:    seedUrl,
+				Source: shared.MailRecords,
+			})
+			continue
+		}
+
+		u := url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: lead.Value}
+		ns.outputUrls([]url.URL{u}, shared.MailRecords)
+		ns.collectSubdomain(lead.Value)
+	}
+
+	for _, lead := range policy.Cidrs {
+		ns.manageFindingChan(shared.Finding{
+			Type:   shared.MailIpRange,
+			Value:  fmt.Sprintf("%s (%s in %s)", lead.Value, lead.Mechanism, lead.Origin),
+			Url:    seedUrl,
+			Source: shared.MailRecords,
+		})
+
+		// ranges listed by the target's own records are likely owned by it
+		if ns.inDomainScope(lead.Origin) {
+			ns.mutex.Lock()
+			if !shared.SliceContains(ns.ipRanges, lead.Value) {
+				ns.ipRanges = append(ns.ipRanges, lead.Value)
+			}
+			ns.mutex.Unlock()
+		}
+	}
+
+	for _, lead := range policy.Reports {
+		ns.manageFindingChan(shared.Finding{
+			Type:   shared.DmarcReportAddress,
+			Value:  fmt.Sprintf("%s (%s)", lead.Value, lead.Mechanism),
+			Url:    seedUrl,
+			Source: shared.MailRecords,
+		})
+	}
+}
+
+// Returns the registrable domain of the seed (e.g. example.com for www.example.com), which the DNS modules enumerate
+// and every discovered host is scoped to
+func (ns *NetScout) scopeDomain() string {
+	return shared.BaseDomain(ns.settings.SeedUrl.Hostname())
+}
+
+// Checks if the host is the seed's registrable domain or one of its subdomains
+func (ns *NetScout) inDomainScope(host string) bool {
+	domain := ns.scopeDomain()
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// Looks up the PTR records of the addresses found so far and the CIDRs set by the user. If the sweep is expanded,
+// the addresses are expanded to their /24 and the ranges listed by the seed's SPF record are swept as well. Host
+// names under the seed's domain are reported as new subdomains, and every address to host name mapping is written
+// to the output file.
+func (ns *NetScout) sweepReverseDns() {
+	if ns.settings.SkipPtrSweep {
+		return
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping reverse DNS sweep")
+		return
+	}
+
+	// the ranges listed by the SPF record can be large and shared with other tenants, so they are only swept on request
+	ns.mutex.Lock()
+	addresses := append([]string{}, ns.addresses...)
+	cidrs := append([]string{}, ns.settings.PtrCidrs...)
+	if ns.settings.PtrExpand {
+		cidrs = append(cidrs, ns.ipRanges...)
+	}
+	ns.mutex.Unlock()
+
+	var targets []string
+	for _, cidr := range cidrs {
+		expanded, err := osint.SweepTargets([]string{}, []string{cidr}, false)
+		if err != nil {
+			ns.displayWarning("skipping " + err.Error())
+			continue
+		}
+
+		targets = append(targets, expanded...)
+	}
+
+	// only the addresses of discovered hosts are expanded, so the ranges are swept as they were given
+	addresses, _ = osint.SweepTargets(addresses, []string{}, ns.settings.PtrExpand)
+	expanded, _ := osint.SweepTargets(append(addresses, targets...), []string{}, false)
+	if len(expanded) == 0 {
+		return
+	}
+
+	ns.displaySuccess(fmt.Sprintf("Sweeping reverse DNS of %d addresses", len(expanded)))
+
+	for _, record := range resolver.ReverseLookup(expanded) {
+		ns.ptrRecords = append(ns.ptrRecords, record)
+		ipUrl := url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: record.IP}
+
+		ns.writeFinding(shared.Finding{
+			Type:   shared.PtrRecord,
+			Value:  record.String(),
+			Url:    ipUrl,
+			Source: shared.ReverseDns,
+		})
+
+		for _, host := range record.Hosts {
+			ns.mutex.Lock()
+			_, known := ns.subdomainMap[host]
+			ns.mutex.Unlock()
+
+			if known || host == ns.scopeDomain() || !ns.inDomainScope(host) {
+				continue
+			}
+
+			ns.manageFindingChan(shared.Finding{
+				Type:   shared.ResolvedSubdomain,
+				Value:  fmt.Sprintf("%s (PTR %s)", host, record.IP),
+				Url:    url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: host},
+				Source: shared.ReverseDns,
+			})
+
+			ns.collectSubdomain(host)
+		}
+	}
+}
+
+func (ns *NetScout) permuteSubdomains() []osint.ResolvedHost {
+	if ns.settings.SkipPermutations {
+		return []osint.ResolvedHost{}
+	}
+
+	ns.mutex.Lock()
+	known := append([]string{}, ns.subdomains...)
+	ns.mutex.Unlock()
+
+	if len(known) == 0 {
+		return []osint.ResolvedHost{}
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping subdomain permutations")
+		return []osint.ResolvedHost{}
+	}
+
+	ns.displaySuccess("Resolving subdomain permutations")
+
+	hosts := resolver.Permute(ns.scopeDomain(), known, ns.settings.PermuteRounds)
+	ns.outputResolvedHosts(hosts, shared.Permutations)
+
+	return hosts
+}
+
+// Queries the records of the seed's domain and every subdomain found. Subdomains the records point to (e.g. MX and
+// SRV targets) are enumerated as well, and targets outside the domain are reported as related hosts.
+func (ns *NetScout) enumerateDnsRecords() {
+	if ns.settings.SkipDnsRecords {
+		return
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping DNS record enumeration")
+		return
+	}
+
+	ns.displaySuccess("Enumerating DNS records")
+
+	domain := ns.scopeDomain()
+
+	ns.mutex.Lock()
+	queue := append([]string{domain}, ns.subdomains...)
+	ns.mutex.Unlock()
+
+	enumerated := map[string]struct{}{}
+	for len(queue) > 0 {
+		for _, host := range queue {
+			enumerated[host] = struct{}{}
+		}
+
+		var next []string
+		for _, hostRecords := range resolver.EnumerateRecords(queue) {
+			ns.dnsRecords = append(ns.dnsRecords, hostRecords)
+			hostUrl := url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: hostRecords.Host}
+
+			for _, record := range hostRecords.Records {
+				if record.Type == "A" || record.Type == "AAAA" {
+					ns.collectAddress(record.Rdata)
+				}
+
+				ns.writeFinding(shared.Finding{
+					Type:   shared.DnsRecord,
+					Value:  fmt.Sprintf("%s %d IN %s %s", record.Name, record.TTL, record.Type, record.Rdata),
+					Url:    hostUrl,
+					Source: shared.DnsRecords,
+				})
+			}
+
+			for _, target := range hostRecords.Targets {
+				if !ns.inDomainScope(target) {
+					ns.manageFindingChan(shared.Finding{
+						Type:   shared.RelatedHost,
+						Value:  target,
+						Url:    hostUrl,
+						Source: shared.DnsRecords,
+					})
+					continue
+				}
+
+				ns.collectSubdomain(target)
+				if _, exists := enumerated[target]; !exists && !shared.SliceContains(next, target) {
+					next = append(next, target)
+				}
+			}
+		}
+
+		queue = next
+	}
+}
+
+func (ns *NetScout) detectTakeovers() {
+	if ns.settings.TakeoverFile == "" {
+		return
+	}
+
+	detector, err := osint.LoadTakeoverFingerprints(ns.settings.TakeoverFile)
+	if err != nil {
+		ns.displayWarning("failed to load takeover fingerprints file - skipping subdomain takeover detection")
+		return
+	}
+
+	resolver, err := ns.subdomainResolver()
+	if err != nil {
+		ns.displayWarning(err.Error() + " - skipping subdomain takeover detection")
+		return
+	}
+
+	ns.mutex.Lock()
+	hosts := append([]string{}, ns.subdomains...)
+	ns.mutex.Unlock()
+
+	if len(hosts) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Checking subdomains for takeovers")
+
+	for _, result := range detector.Check(resolver, hosts) {
+		findingType := shared.SubdomainTakeover
+		if result.Status == osint.LikelyVulnerable {
+			findingType = shared.LikelySubdomainTakeover
+		}
+
+		ns.manageFindingChan(shared.Finding{
+			Type:   findingType,
+			Value:  result.String(),
+			Url:    url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: result.Host},
+			Source: shared.Takeover,
+		})
+	}
+}
+
+// Returns the resolver shared by the DNS modules, creating it the first time it is needed
+func (ns *NetScout) subdomainResolver() (*osint.SubdomainResolver, error) {
+	if ns.resolver != nil {
+		return ns.resolver, nil
+	}
+
+	resolver, err := osint.NewSubdomainResolver(ns.settings.Resolvers, ns.settings.DnsRate, ns.settings.DnsWorkers, dnsTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// resolvers that fail are skipped until they recover
+	for _, unhealthy := range resolver.CheckHealth() {
+		ns.displayWarning("DNS resolver " + unhealthy + " is not answering - skipping it for now")
+	}
+
+	ns.resolver = resolver
+	return resolver, nil
+}
+
+// Reports the resolved subdomains along with their records, and keeps them for the permutations
+func (ns *NetScout) outputResolvedHosts(hosts []osint.ResolvedHost, source shared.Source) {
+	for _, host := range hosts {
+		ns.manageFindingChan(shared.Finding{
+			Type:   shared.ResolvedSubdomain,
+			Value:  host.String(),
+			Url:    url.URL{Scheme: ns.settings.SeedUrl.Scheme, Host: host.Name},
+			Source: source,
+		})
+
+		ns.collectSubdomain(host.Name)
+
+		for _, address := range append(append([]string{}, host.A...), host.AAAA...) {
+			ns.collectAddress(address)
+		}
+	}
+}
+
+// Keeps an address a discovered host resolves to, for the reverse DNS sweep
+func (ns *NetScout) collectAddress(address string) {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if _, exists := ns.addressMap[address]; exists {
+		return
+	}
+
+	ns.addressMap[address] = struct{}{}
+	ns.addresses = append(ns.addresses, address)
+}
+
+func (ns *NetScout) crawl(toCrawl []url.URL, comms shared.CommsChannels) *osint.Crawler {
 	ns.displaySuccess("Starting crawl")
 
 	crawler := osint.NewCrawler(
@@ -189,7 +733,122 @@ func (ns *NetScout) crawl(toCrawl []url.URL, comms shared.CommsChannels) {
 		ns.settings.Cookie,
 	)
 
+	if !ns.settings.SkipSoft404 {
+		crawler.EnableSoft404Detection()
+	}
+
+	if !ns.settings.SkipDirListing {
+		crawler.EnableDirectoryListingWalk(ns.settings.ListingBudget)
+	}
+
+	if !ns.settings.SkipContacts {
+		crawler.EnableContactExtraction()
+	}
+
+	if !ns.settings.SkipComments {
+		filter := osint.NewCommentFilter(ns.settings.CommentKeywords, ns.settings.CommentMinLength)
+		crawler.EnableCommentHarvest(filter)
+	}
+
+	if ns.settings.FingerprintsFile != "" {
+		fingerprinter, err := osint.LoadFingerprinter(ns.settings.FingerprintsFile)
+		if err != nil {
+			ns.displayWarning("failed to load fingerprints file - skipping technology fingerprinting")
+		} else {
+			crawler.EnableFingerprinting(fingerprinter)
+		}
+	}
+
+	if !ns.settings.SkipFavicons {
+		crawler.EnableFaviconHashing()
+	}
+
+	if !ns.settings.SkipHeaderAudit {
+		crawler.EnableHeaderAudit()
+	}
+
+	if !ns.settings.SkipSourceMaps {
+		crawler.EnableSourceMapRecovery(ns.settings.SourceMapDir)
+	}
+
+	if !ns.settings.SkipApiDiscovery {
+		crawler.EnableApiDiscovery()
+	}
+
+	if !ns.settings.SkipRealtime {
+		crawler.EnableRealtimeDiscovery()
+	}
+
+	if !ns.settings.SkipWellKnown {
+		crawler.EnableWellKnownProbing()
+	}
+
+	if !ns.settings.SkipVcs {
+		crawler.EnableVcsProbing()
+	}
+
+	if ns.settings.Wordlist != "" {
+		words, err := osint.LoadWordlist(ns.settings.Wordlist)
+		if err != nil {
+			ns.displayWarning("failed to load wordlist - skipping content discovery")
+		} else {
+			filter := osint.ResponseFilter{
+				MatchStatus: ns.settings.MatchStatus,
+				FilterSizes: ns.settings.FilterSizes,
+				FilterWords: ns.settings.FilterWords,
+			}
+
+			cd := osint.NewContentDiscovery(words, ns.settings.DiscoveryExtensions, filter, ns.settings.Recursion)
+			crawler.EnableContentDiscovery(cd)
+		}
+	}
+
 	crawler.Crawl(0)
+
+	ns.headerAudits = crawler.HeaderAudits()
+
+	return &crawler
+}
+
+func (ns *NetScout) probeBackups(crawler *osint.Crawler) {
+	if ns.settings.SkipBackups {
+		return
+	}
+
+	ns.mutex.Lock()
+	files := ns.files
+	ns.mutex.Unlock()
+
+	if len(files) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Probing backup files")
+
+	crawler.ProbeBackups(files)
+}
+
+func (ns *NetScout) analyzeDocuments(crawler *osint.Crawler) {
+	if !ns.settings.AnalyzeDocs {
+		return
+	}
+
+	ns.mutex.Lock()
+	var documents []url.URL
+	for _, file := range ns.files {
+		if osint.IsAnalyzableDocument(file.Path) {
+			documents = append(documents, file)
+		}
+	}
+	ns.mutex.Unlock()
+
+	if len(documents) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Analyzing documents")
+
+	crawler.AnalyzeDocuments(documents, int64(ns.settings.MaxDocSize)*1024*1024)
 }
 
 func (ns *NetScout) getFiletypeResults() ([]url.URL, error) {
@@ -225,23 +884,27 @@ func (ns *NetScout) handleComms(comms shared.CommsChannels) {
 	crawlFinish := false
 	shortenedFinish := false
 
-	var wg sync.WaitGroup
-	defer wg.Done()
-
 	msgDisplayed := false
 
 	for {
 		select {
 		case msg := <-comms.DataChan:
-			ns.manageDataChan(msg, &wg)
+			ns.manageDataChan(msg, &ns.collectors)
+		case finding := <-comms.FindingChan:
+			ns.manageFindingChan(finding)
 		case msg := <-comms.WarningChan:
 			ns.displayWarning(msg)
 		case <-comms.CrawlDoneChan:
+			// the crawler's messages are handled in order, so a collector was started for each of them by now
+			if !crawlFinish {
+				ns.collectors.Done()
+			}
+
 			ns.manageDoneChan(
 				shortenedFinish,
 				crawlFinish,
 				msgDisplayed,
-				&wg,
+				&ns.collectors,
 				"In progress: Shortened URL scan (this can take several minutes)",
 			)
 
@@ -252,7 +915,7 @@ func (ns *NetScout) handleComms(comms shared.CommsChannels) {
 				shortenedFinish,
 				crawlFinish,
 				msgDisplayed,
-				&wg,
+				&ns.collectors,
 				"In progress: Crawler",
 			)
 
@@ -282,9 +945,33 @@ func (ns *NetScout) manageDataChan(msg shared.ScannedItem, wg *sync.WaitGroup) {
 
 	go ns.CollectFiletypes(msg.Url, wg)
 
+	ns.collectSubdomain(msg.Url.Hostname())
+
 	ns.displayMsg(msg.Url.String())
 }
 
+// Manages the incoming findings (e.g. emails, phone numbers) by writing them to the output file and displaying them
+func (ns *NetScout) manageFindingChan(finding shared.Finding) {
+	ns.mutex.Lock()
+	if ns.settings.Output != "" {
+		ns.outputFile.Write([]byte(finding.Format()))
+	}
+
+	switch finding.Type {
+	case shared.Technology:
+		host := finding.Url.Host
+		ns.technologies[host] = append(ns.technologies[host], finding.Value)
+	case shared.FaviconHash:
+		host := finding.Url.Host
+		if !shared.SliceContains(ns.favicons[finding.Value], host) {
+			ns.favicons[finding.Value] = append(ns.favicons[finding.Value], host)
+		}
+	}
+	ns.mutex.Unlock()
+
+	ns.displayFinding(finding)
+}
+
 // Manages the incoming messages from the done channel
 func (ns *NetScout) manageDoneChan(
 	shortenedFinish bool,
@@ -322,6 +1009,57 @@ func (ns *NetScout) CollectFiletypes(url url.URL, wg *sync.WaitGroup) {
 			ns.updateExtensions(value, url)
 		}
 	}
+
+	ns.collectFile(url)
+}
+
+// Keeps file-like URLs (e.g. /config.php), which are probed for backup files and analyzed for metadata at the
+// end of the scan
+func (ns *NetScout) collectFile(u url.URL) {
+	if filepath.Ext(path.Base(u.Path)) == "" {
+		return
+	}
+
+	file := url.URL{Scheme: u.Scheme, Host: u.Host, Path: u.Path}
+
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if _, exists := ns.fileMap[file.String()]; exists {
+		return
+	}
+
+	ns.fileMap[file.String()] = struct{}{}
+	ns.files = append(ns.files, file)
+}
+
+// Keeps the host names under the seed's registrable domain found by any module, which are permuted after the crawl
+func (ns *NetScout) collectSubdomain(host string) {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == ns.scopeDomain() || !ns.inDomainScope(host) {
+		return
+	}
+
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if _, exists := ns.subdomainMap[host]; exists {
+		return
+	}
+
+	ns.subdomainMap[host] = struct{}{}
+	ns.subdomains = append(ns.subdomains, host)
+}
+
+// Keeps the subdomains of URLs that may be missing a scheme (e.g. "dev.example.com" from a zone transfer)
+func (ns *NetScout) collectSubdomains(urls []url.URL) {
+	for _, u := range urls {
+		if u.Host != "" {
+			ns.collectSubdomain(u.Hostname())
+		} else {
+			ns.collectSubdomain(u.Path)
+		}
+	}
 }
 
 func (ns *NetScout) updateExtensions(file string, url url.URL) {
@@ -351,6 +1089,184 @@ func (ns *NetScout) outputUrls(urls []url.URL, source shared.Source) {
 	}
 }
 
+// Displays the technologies detected for each host
+func (ns *NetScout) displayTechnologySummary() {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if len(ns.technologies) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Technologies per host")
+
+	hosts := make([]string, 0, len(ns.technologies))
+	for host := range ns.technologies {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 1, 2, ' ', 0)
+	for _, host := range hosts {
+		fmt.Fprintf(writer, "%s\t%s\n", host, strings.Join(ns.technologies[host], ", "))
+	}
+	writer.Flush()
+}
+
+// Displays the favicon hashes found, grouping the hosts that share the same favicon
+func (ns *NetScout) displayFaviconSummary() {
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	if len(ns.favicons) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Favicons shared by hosts")
+
+	hashes := make([]string, 0, len(ns.favicons))
+	for hash := range ns.favicons {
+		hashes = append(hashes, hash)
+	}
+
+	// favicons shared by the most hosts are displayed first
+	sort.Slice(hashes, func(i, j int) bool {
+		if len(ns.favicons[hashes[i]]) != len(ns.favicons[hashes[j]]) {
+			return len(ns.favicons[hashes[i]]) > len(ns.favicons[hashes[j]])
+		}
+		return hashes[i] < hashes[j]
+	})
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 1, 2, ' ', 0)
+	for _, hash := range hashes {
+		hosts := ns.favicons[hash]
+		sort.Strings(hosts)
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", hash, len(hosts), strings.Join(hosts, ", "))
+	}
+	writer.Flush()
+}
+
+// Displays a table with the security headers of each host. A header's value is displayed when it is the same
+// across all audited paths, otherwise it is displayed as "partial" (missing in some paths) or "mixed".
+func (ns *NetScout) displayHeaderAuditSummary() {
+	if len(ns.headerAudits) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Security headers per host")
+
+	auditsPerHost := map[string][]osint.HeaderAudit{}
+	for _, audit := range ns.headerAudits {
+		auditsPerHost[audit.Host] = append(auditsPerHost[audit.Host], audit)
+	}
+
+	hosts := make([]string, 0, len(auditsPerHost))
+	for host := range auditsPerHost {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 1, 2, ' ', 0)
+	fmt.Fprintf(writer, "HOST\tPATHS\t%s\tINSECURE COOKIES\n", strings.Join(osint.AuditedHeaders, "\t"))
+
+	for _, host := range hosts {
+		audits := auditsPerHost[host]
+		columns := []string{host, strconv.Itoa(len(audits))}
+
+		for _, header := range osint.AuditedHeaders {
+			columns = append(columns, summarizeHeader(audits, header))
+		}
+
+		insecureCookies := 0
+		for _, audit := range audits {
+			for _, issue := range audit.Issues {
+				if issue.Type == shared.InsecureCookie {
+					insecureCookies++
+				}
+			}
+		}
+
+		columns = append(columns, strconv.Itoa(insecureCookies))
+		fmt.Fprintln(writer, strings.Join(columns, "\t"))
+	}
+	writer.Flush()
+}
+
+// Summarizes the values of a header across all audits of a host
+func summarizeHeader(audits []osint.HeaderAudit, header string) string {
+	const maxLength = 30
+
+	value, exists := audits[0].Values[header]
+	for _, audit := range audits[1:] {
+		other, otherExists := audit.Values[header]
+		if otherExists != exists {
+			return "partial"
+		}
+
+		if other != value {
+			return "mixed"
+		}
+	}
+
+	if !exists {
+		return "-"
+	}
+
+	if len(value) > maxLength {
+		return value[:maxLength-3] + "..."
+	}
+
+	return value
+}
+
+// Displays the host names every swept address points back to
+func (ns *NetScout) displayPtrSummary() {
+	if len(ns.ptrRecords) == 0 {
+		return
+	}
+
+	ns.displaySuccess("Reverse DNS")
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(writer, "ADDRESS\tHOSTS")
+
+	for _, record := range ns.ptrRecords {
+		fmt.Fprintf(writer, "%s\t%s\n", record.IP, strings.Join(record.Hosts, ", "))
+	}
+	writer.Flush()
+}
+
+// Displays the DNS records of every host
+func (ns *NetScout) displayDnsSummary() {
+	if len(ns.dnsRecords) == 0 {
+		return
+	}
+
+	ns.displaySuccess("DNS records per host")
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 1, 2, ' ', 0)
+	fmt.Fprintln(writer, "HOST\tNAME\tTYPE\tTTL\tDATA")
+
+	for _, hostRecords := range ns.dnsRecords {
+		for _, record := range hostRecords.Records {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%d\t%s\n", hostRecords.Host, record.Name, record.Type, record.TTL, record.Rdata)
+		}
+	}
+	writer.Flush()
+}
+
+// Writes the finding to the output file without displaying it, for findings summarized at the end of the scan
+func (ns *NetScout) writeFinding(finding shared.Finding) {
+	if ns.settings.Output == "" {
+		return
+	}
+
+	ns.mutex.Lock()
+	defer ns.mutex.Unlock()
+
+	ns.outputFile.Write([]byte(finding.Format()))
+}
+
 // Displays warnings from error list
 func (ns *NetScout) outputWarnings(errs []error) {
 	for _, err := range errs {
@@ -362,6 +1278,15 @@ func (ns *NetScout) displayMsg(item string) {
 	fmt.Printf("\n\033[A%s[x]%s %s\n", green, reset, item)
 }
 
+func (ns *NetScout) displayFinding(finding shared.Finding) {
+	color := green
+	if finding.IsHighInterest() {
+		color = red
+	}
+
+	fmt.Printf("\n\033[A%s[x]%s %s%s%s %s\n", color, reset, bold, finding.Type, reset, finding.Value)
+}
+
 func (ns *NetScout) displaySuccess(text string) {
 	fmt.Printf("\n\033[A%s[x] %s%s\n", green, text, reset)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
 )
 
@@ -14,25 +15,66 @@ const (
 	invalidKeyValuePair = "provided key-value pair is not valid"
 	invalidCookieStr    = "provided cookie string is not valid"
 	invalidHeaderStr    = "provided header string is not valid"
+	invalidIntList      = "provided integer list is not valid"
 )
 
 type Settings struct {
-	Headless         bool
-	SeedUrl          url.URL
-	Depth            int
-	LockHost         bool
-	ThreadCount      int
-	ReqDelay         int
-	Output           string
-	Verbose          bool
-	Cookie           map[string]string
-	Header           map[string]string
-	BinaryEdgeApiKey string
-	SerpApiKey       string
-	SkipBinaryEdge   bool
-	SkipGoogleDork   bool
-	SkipAXFR         bool
-	Deep             bool
+	Headless            bool
+	SeedUrl             url.URL
+	Depth               int
+	LockHost            bool
+	ThreadCount         int
+	ReqDelay            int
+	Output              string
+	Verbose             bool
+	Cookie              map[string]string
+	Header              map[string]string
+	BinaryEdgeApiKey    string
+	SerpApiKey          string
+	SkipBinaryEdge      bool
+	SkipGoogleDork      bool
+	SkipAXFR            bool
+	ZoneFile            string
+	AxfrTimeout         int
+	SkipZoneWalk        bool
+	DnsWordlist         string
+	Resolvers           []string
+	DnsRate             int
+	DnsWorkers          int
+	SkipPermutations    bool
+	PermuteRounds       int
+	SkipDnsRecords      bool
+	TakeoverFile        string
+	SkipMailRecords     bool
+	SkipPtrSweep        bool
+	PtrExpand           bool
+	PtrCidrs            []string
+	SkipContacts        bool
+	SkipComments        bool
+	CommentKeywords     []string
+	CommentMinLength    int
+	FingerprintsFile    string
+	SkipFavicons        bool
+	SkipHeaderAudit     bool
+	SkipSourceMaps      bool
+	SourceMapDir        string
+	SkipApiDiscovery    bool
+	SkipRealtime        bool
+	SkipWellKnown       bool
+	SkipVcs             bool
+	Wordlist            string
+	DiscoveryExtensions []string
+	Recursion           int
+	MatchStatus         []int
+	FilterSizes         []int
+	FilterWords         []int
+	SkipSoft404         bool
+	SkipBackups         bool
+	SkipDirListing      bool
+	ListingBudget       int
+	AnalyzeDocs         bool
+	MaxDocSize          int
+	Deep                bool
 }
 
 func ParseFlags() (Settings, error) {
@@ -50,6 +92,46 @@ func ParseFlags() (Settings, error) {
 	skipBinaryEdgePtr := flag.Bool("skip-binaryedge", false, "A bool - if set, it will skip BinaryEdge subdomain scan")
 	skipGoogleDorkPtr := flag.Bool("skip-google-dork", false, "A bool - if set, it will skip the Google filetype scan")
 	skipAXFRPtr := flag.Bool("skip-axfr", false, "A bool - if set, it will skip the DNS zone trasnfer attempt")
+	zoneFilePtr := flag.String("zone-file", "", "A string representing the name of the file the records of a successful zone transfer are exported to")
+	axfrTimeoutPtr := flag.Int("axfr-timeout", 10, "An integer representing the timeout in seconds of the zone transfer attempt against each name server")
+	skipZoneWalkPtr := flag.Bool("skip-zone-walk", false, "A bool - if set, it will skip walking the NSEC/NSEC3 records of the seed's DNSSEC-signed zone")
+	subdomainWordlistPtr := flag.String("subdomain-wordlist", "", "A string representing the path to a wordlist used to brute force the subdomains of the seed's domain")
+	resolversPtr := flag.String("resolvers", "1.1.1.1,8.8.8.8,9.9.9.9", "A comma-separated string of DNS resolvers used by every DNS module, over UDP, TCP, DNS-over-TLS or DNS-over-HTTPS (e.g. 1.1.1.1,tcp://9.9.9.9,tls://1.1.1.1,https://dns.google/dns-query)")
+	dnsRatePtr := flag.Int("dns-rate", 100, "An integer representing the maximum amount of DNS queries per second sent to each resolver (0 for no limit)")
+	dnsWorkersPtr := flag.Int("dns-workers", 10, "An integer representing the amount of concurrent DNS workers")
+	skipPermutationsPtr := flag.Bool("skip-permutations", false, "A bool - if set, it will skip resolving permutations of the subdomains found")
+	permutationRoundsPtr := flag.Int("permutation-rounds", 2, "An integer representing the maximum amount of rounds of permutations of newly found subdomains")
+	skipDnsRecordsPtr := flag.Bool("skip-dns-records", false, "A bool - if set, it will skip querying the DNS records of the seed's domain and its subdomains")
+	takeoverFilePtr := flag.String("takeover-fingerprints", "", "A string representing the path to a can-i-take-over-xyz fingerprints file used to detect subdomain takeovers")
+	skipMailRecordsPtr := flag.Bool("skip-mail-records", false, "A bool - if set, it will skip following the SPF, DMARC and DKIM records of the seed's domain")
+	skipPtrSweepPtr := flag.Bool("skip-ptr-sweep", false, "A bool - if set, it will skip the reverse DNS sweep of the addresses of discovered hosts")
+	ptrExpandPtr := flag.Bool("ptr-expand", false, "A bool - if set, the reverse DNS sweep is expanded to the /24 of every IPv4 address found and to the IP ranges listed by the seed's SPF record")
+	ptrCidrsPtr := flag.String("ptr-cidrs", "", "A comma-separated string of IP ranges to sweep for PTR records (e.g. 192.0.2.0/24,198.51.100.0/28)")
+	skipCommentsPtr := flag.Bool("skip-comments", false, "A bool - if set, it will skip the harvesting of HTML and JavaScript comments")
+	commentKeywordsPtr := flag.String("comment-keywords", "", "A comma-separated string of keywords used to filter harvested comments (defaults to a built-in list)")
+	commentMinLengthPtr := flag.Int("comment-min-len", 10, "An integer representing the minimum length of a harvested comment")
+	fingerprintsPtr := flag.String("fingerprints", "", "A string representing the path to a Wappalyzer-compatible signatures file used for technology fingerprinting")
+	skipFaviconsPtr := flag.Bool("skip-favicons", false, "A bool - if set, it will skip fetching and hashing the favicons of discovered hosts")
+	skipHeaderAuditPtr := flag.Bool("skip-header-audit", false, "A bool - if set, it will skip the audit of security headers and cookie flags")
+	skipSourceMapsPtr := flag.Bool("skip-sourcemaps", false, "A bool - if set, it will skip probing JavaScript files for source maps")
+	sourceMapDirPtr := flag.String("sourcemap-dir", "", "A string representing the directory where the original sources recovered from source maps are written")
+	skipApiDiscoveryPtr := flag.Bool("skip-api-discovery", false, "A bool - if set, it will skip probing hosts for OpenAPI/Swagger specifications and GraphQL endpoints")
+	skipRealtimePtr := flag.Bool("skip-realtime", false, "A bool - if set, it will skip the discovery of WebSocket and Server-Sent Events endpoints")
+	skipWellKnownPtr := flag.Bool("skip-well-known", false, "A bool - if set, it will skip probing hosts for well-known files (e.g. security.txt, openid-configuration, crossdomain.xml)")
+	skipVcsPtr := flag.Bool("skip-vcs", false, "A bool - if set, it will skip probing hosts and directories for exposed .git, .svn and .hg repositories and .DS_Store files")
+	wordlistPtr := flag.String("wordlist", "", "A string representing the path to a wordlist used to brute force the paths of every in-scope host and directory")
+	extensionsPtr := flag.String("extensions", "", "A comma-separated string of extensions appended to every word of the wordlist (e.g. php,bak)")
+	recursionPtr := flag.Int("recursion", 1, "An integer representing how many levels deep directories found by the wordlist are brute forced")
+	matchStatusPtr := flag.String("match-status", "200,204,301,302,307,308,401,403,405", "A comma-separated string of status codes reported by the wordlist brute force")
+	filterSizePtr := flag.String("filter-size", "", "A comma-separated string of response sizes ignored by the wordlist brute force")
+	filterWordsPtr := flag.String("filter-words", "", "A comma-separated string of response word counts ignored by the wordlist brute force")
+	skipSoft404Ptr := flag.Bool("skip-soft404", false, "A bool - if set, it will skip the detection of soft-404 pages, which are otherwise reported and not expanded")
+	skipBackupsPtr := flag.Bool("skip-backups", false, "A bool - if set, it will skip probing discovered files for backup and variant copies (e.g. .bak, .old, ~)")
+	skipDirListingPtr := flag.Bool("skip-dir-listing", false, "A bool - if set, it will skip the detection and traversal of directory listings")
+	listingBudgetPtr := flag.Int("listing-budget", 100, "An integer representing the maximum amount of subdirectory listings fetched, regardless of the crawl depth")
+	analyzeDocsPtr := flag.Bool("analyze-docs", false, "A bool - if set, it will download the PDF, Office documents and images found and extract their metadata")
+	maxDocSizePtr := flag.Int("max-doc-size", 10, "An integer representing the maximum size in megabytes of a document downloaded for analysis")
+	skipContactsPtr := flag.Bool("skip-contacts", false, "A bool - if set, it will skip the extraction of emails, phone numbers and social media profiles")
 	deepPtr := flag.Bool("deep", false, "A boolean - if set, it will perform a shortened URL scan (can take several minutes)")
 
 	flag.Parse()
@@ -85,27 +167,99 @@ func ParseFlags() (Settings, error) {
 		headerMap = headers
 	}
 
+	var commentKeywords []string
+	if *commentKeywordsPtr != "" {
+		commentKeywords = strings.Split(*commentKeywordsPtr, ",")
+	}
+
+	resolvers := strings.Split(*resolversPtr, ",")
+
+	var ptrCidrs []string
+	if *ptrCidrsPtr != "" {
+		ptrCidrs = strings.Split(*ptrCidrsPtr, ",")
+	}
+
+	var extensions []string
+	if *extensionsPtr != "" {
+		extensions = strings.Split(*extensionsPtr, ",")
+	}
+
+	matchStatus, err := parseIntList(*matchStatusPtr)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	filterSizes, err := parseIntList(*filterSizePtr)
+	if err != nil {
+		return Settings{}, err
+	}
+
+	filterWords, err := parseIntList(*filterWordsPtr)
+	if err != nil {
+		return Settings{}, err
+	}
+
 	// Defaults to empty string
 	binaryEdgeApiKey := os.Getenv("BINARYEDGE_API_KEY")
 	serpApiKey := os.Getenv("SERP_API_KEY")
 
 	return Settings{
-		Headless:         *headlessPtr,
-		SeedUrl:          *parsedUrl,
-		Depth:            *depthPtr,
-		LockHost:         *lockHostPtr,
-		ThreadCount:      *threadCountPtr,
-		ReqDelay:         *reqDelayPtr,
-		Output:           *outputPtr,
-		Verbose:          *verbosePtr,
-		Cookie:           cookieMap,
-		Header:           headerMap,
-		BinaryEdgeApiKey: binaryEdgeApiKey,
-		SerpApiKey:       serpApiKey,
-		SkipBinaryEdge:   *skipBinaryEdgePtr,
-		SkipGoogleDork:   *skipGoogleDorkPtr,
-		SkipAXFR:         *skipAXFRPtr,
-		Deep:             *deepPtr,
+		Headless:            *headlessPtr,
+		SeedUrl:             *parsedUrl,
+		Depth:               *depthPtr,
+		LockHost:            *lockHostPtr,
+		ThreadCount:         *threadCountPtr,
+		ReqDelay:            *reqDelayPtr,
+		Output:              *outputPtr,
+		Verbose:             *verbosePtr,
+		Cookie:              cookieMap,
+		Header:              headerMap,
+		BinaryEdgeApiKey:    binaryEdgeApiKey,
+		SerpApiKey:          serpApiKey,
+		SkipBinaryEdge:      *skipBinaryEdgePtr,
+		SkipGoogleDork:      *skipGoogleDorkPtr,
+		SkipAXFR:            *skipAXFRPtr,
+		ZoneFile:            *zoneFilePtr,
+		AxfrTimeout:         *axfrTimeoutPtr,
+		SkipZoneWalk:        *skipZoneWalkPtr,
+		DnsWordlist:         *subdomainWordlistPtr,
+		Resolvers:           resolvers,
+		DnsRate:             *dnsRatePtr,
+		DnsWorkers:          *dnsWorkersPtr,
+		SkipPermutations:    *skipPermutationsPtr,
+		PermuteRounds:       *permutationRoundsPtr,
+		SkipDnsRecords:      *skipDnsRecordsPtr,
+		TakeoverFile:        *takeoverFilePtr,
+		SkipMailRecords:     *skipMailRecordsPtr,
+		SkipPtrSweep:        *skipPtrSweepPtr,
+		PtrExpand:           *ptrExpandPtr,
+		PtrCidrs:            ptrCidrs,
+		SkipContacts:        *skipContactsPtr,
+		SkipComments:        *skipCommentsPtr,
+		CommentKeywords:     commentKeywords,
+		CommentMinLength:    *commentMinLengthPtr,
+		FingerprintsFile:    *fingerprintsPtr,
+		SkipFavicons:        *skipFaviconsPtr,
+		SkipHeaderAudit:     *skipHeaderAuditPtr,
+		SkipSourceMaps:      *skipSourceMapsPtr,
+		SourceMapDir:        *sourceMapDirPtr,
+		SkipApiDiscovery:    *skipApiDiscoveryPtr,
+		SkipRealtime:        *skipRealtimePtr,
+		SkipWellKnown:       *skipWellKnownPtr,
+		SkipVcs:             *skipVcsPtr,
+		Wordlist:            *wordlistPtr,
+		DiscoveryExtensions: extensions,
+		Recursion:           *recursionPtr,
+		MatchStatus:         matchStatus,
+		FilterSizes:         filterSizes,
+		FilterWords:         filterWords,
+		SkipSoft404:         *skipSoft404Ptr,
+		SkipBackups:         *skipBackupsPtr,
+		SkipDirListing:      *skipDirListingPtr,
+		ListingBudget:       *listingBudgetPtr,
+		AnalyzeDocs:         *analyzeDocsPtr,
+		MaxDocSize:          *maxDocSizePtr,
+		Deep:                *deepPtr,
 	}, nil
 }
 
@@ -125,3 +279,22 @@ func parseKeyValueStr(str string) (map[string]string, error) {
 
 	return output, nil
 }
+
+// Parses comma-separated lists of integers (e.g. status codes)
+func parseIntList(str string) ([]int, error) {
+	var output []int
+	if str == "" {
+		return output, nil
+	}
+
+	for _, item := range strings.Split(str, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(item))
+		if err != nil {
+			return output, fmt.Errorf(invalidIntList)
+		}
+
+		output = append(output, value)
+	}
+
+	return output, nil
+}
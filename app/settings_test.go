@@ -46,3 +46,38 @@ func TestParseKeyValStr(t *testing.T) {
 		}
 	}
 }
+
+func TestParseIntList(t *testing.T) {
+	cases := map[string]struct {
+		input  string
+		result []int
+		err    bool
+	}{
+		"empty": {
+			input:  "",
+			result: nil,
+			err:    false,
+		},
+		"multipleValues": {
+			input:  "200, 301,403",
+			result: []int{200, 301, 403},
+			err:    false,
+		},
+		"invalidStr": {
+			input:  "200,abc",
+			result: []int{200},
+			err:    true,
+		},
+	}
+
+	for name, tc := range cases {
+		res, err := parseIntList(tc.input)
+		if tc.err && err == nil {
+			t.Errorf("%s expected error but got none", name)
+		}
+
+		if !reflect.DeepEqual(tc.result, res) {
+			t.Errorf("%s expected %v but got %v", name, tc.result, res)
+		}
+	}
+}
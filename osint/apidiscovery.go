@@ -0,0 +1,382 @@
+package osint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	notApiSpecErr       = "document is not an OpenAPI or Swagger specification"
+	notIntrospectionErr = "response is not a GraphQL introspection result"
+)
+
+// Wording of the errors GraphQL servers answer queries they reject with
+var graphqlErrorRegex = regexp.MustCompile(`(?i)graphql|introspection|__schema|cannot query field|must provide (a )?query|syntax error|unknown (type|field|argument)|not allowed by graphql`)
+
+// Common locations of OpenAPI and Swagger specifications
+var apiSpecPaths = []string{
+	"/swagger.json",
+	"/swagger.yaml",
+	"/swagger/v1/swagger.json",
+	"/swagger/doc.json",
+	"/api/swagger.json",
+	"/api/swagger.yaml",
+	"/openapi.json",
+	"/openapi.yaml",
+	"/api/openapi.json",
+	"/api/openapi.yaml",
+	"/api-docs",
+	"/api-docs.json",
+	"/v2/api-docs",
+	"/v3/api-docs",
+	"/api/v1/swagger.json",
+	"/api/v1/openapi.json",
+	"/docs/openapi.json",
+	"/.well-known/openapi.json",
+}
+
+// Common locations of GraphQL endpoints
+var graphqlPaths = []string{
+	"/graphql",
+	"/api/graphql",
+	"/graphql/v1",
+	"/v1/graphql",
+	"/gql",
+	"/query",
+}
+
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+const introspectionQuery = `{"query":"query IntrospectionQuery { __schema { queryType { name } mutationType { name } subscriptionType { name } types { name kind fields { name } } } }"}`
+
+// Path and method described by an API specification (e.g. GET /v1/users)
+type ApiOperation struct {
+	Method string
+	Path   string
+}
+
+func (op ApiOperation) String() string {
+	return op.Method + " " + op.Path
+}
+
+// Subset of a GraphQL introspection result
+type GraphQLSchema struct {
+	Operations []string
+	Types      []string
+}
+
+type graphqlType struct {
+	Name   string `json:"name"`
+	Kind   string `json:"kind"`
+	Fields []struct {
+		Name string `json:"name"`
+	} `json:"fields"`
+}
+
+// Parses an OpenAPI 3 or Swagger 2 specification in either JSON or YAML, and returns every operation
+// prefixed with the Swagger basePath
+func ParseApiSpec(data []byte) ([]ApiOperation, error) {
+	var spec struct {
+		Swagger  string                                `json:"swagger"`
+		OpenApi  string                                `json:"openapi"`
+		BasePath string                                `json:"basePath"`
+		Paths    map[string]map[string]json.RawMessage `json:"paths"`
+	}
+
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return parseYamlApiSpec(string(data))
+	}
+
+	if spec.Swagger == "" && spec.OpenApi == "" {
+		return nil, fmt.Errorf(notApiSpecErr)
+	}
+
+	var operations []ApiOperation
+	for path, item := range spec.Paths {
+		for method := range item {
+			if shared.SliceContains(httpMethods, strings.ToLower(method)) {
+				operations = append(operations, ApiOperation{
+					Method: strings.ToUpper(method),
+					Path:   joinBasePath(spec.BasePath, path),
+				})
+			}
+		}
+	}
+
+	sortOperations(operations)
+
+	return operations, nil
+}
+
+// Extracts the operations of a YAML specification based on indentation, which is enough for the
+// "paths" section without a full YAML parser
+func parseYamlApiSpec(data string) ([]ApiOperation, error) {
+	isSpec := false
+	basePath := ""
+
+	var operations []ApiOperation
+	inPaths := false
+	pathIndent, methodIndent := -1, -1
+	currentPath := ""
+
+	for _, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+
+		key = strings.Trim(key, `"'`)
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+
+		if indent == 0 {
+			inPaths = key == "paths"
+
+			switch key {
+			case "swagger", "openapi":
+				isSpec = true
+			case "basePath":
+				basePath = value
+			}
+
+			continue
+		}
+
+		if !inPaths {
+			continue
+		}
+
+		if pathIndent == -1 {
+			pathIndent = indent
+		}
+
+		switch {
+		case indent == pathIndent:
+			currentPath = key
+			methodIndent = -1
+		case indent > pathIndent && currentPath != "":
+			if methodIndent == -1 {
+				methodIndent = indent
+			}
+
+			if indent == methodIndent && shared.SliceContains(httpMethods, strings.ToLower(key)) {
+				operations = append(operations, ApiOperation{Method: strings.ToUpper(key), Path: currentPath})
+			}
+		}
+	}
+
+	if !isSpec {
+		return nil, fmt.Errorf(notApiSpecErr)
+	}
+
+	for i := range operations {
+		operations[i].Path = joinBasePath(basePath, operations[i].Path)
+	}
+
+	sortOperations(operations)
+
+	return operations, nil
+}
+
+// Parses the response of an introspection query into the schema's operations and types.
+// Built-in types (e.g. __Schema, String) are omitted.
+func ParseIntrospection(data []byte) (GraphQLSchema, error) {
+	var resp struct {
+		Data struct {
+			Schema *struct {
+				QueryType        *struct{ Name string } `json:"queryType"`
+				MutationType     *struct{ Name string } `json:"mutationType"`
+				SubscriptionType *struct{ Name string } `json:"subscriptionType"`
+				Types            []graphqlType          `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return GraphQLSchema{}, err
+	}
+
+	schema := resp.Data.Schema
+	if schema == nil {
+		return GraphQLSchema{}, fmt.Errorf(notIntrospectionErr)
+	}
+
+	roots := map[string]string{}
+	if schema.QueryType != nil {
+		roots[schema.QueryType.Name] = "query"
+	}
+	if schema.MutationType != nil {
+		roots[schema.MutationType.Name] = "mutation"
+	}
+	if schema.SubscriptionType != nil {
+		roots[schema.SubscriptionType.Name] = "subscription"
+	}
+
+	builtIn := []string{"String", "Int", "Float", "Boolean", "ID"}
+
+	var output GraphQLSchema
+	for _, t := range schema.Types {
+		if operationType, isRoot := roots[t.Name]; isRoot {
+			for _, field := range t.Fields {
+				output.Operations = append(output.Operations, operationType+" "+field.Name)
+			}
+			continue
+		}
+
+		if strings.HasPrefix(t.Name, "__") || shared.SliceContains(builtIn, t.Name) {
+			continue
+		}
+
+		output.Types = append(output.Types, fmt.Sprintf("%s (%s)", t.Name, t.Kind))
+	}
+
+	return output, nil
+}
+
+// Checks if a response body looks like it came from a GraphQL server, even if introspection is disabled: either it
+// holds the introspected schema, or a list of GraphQL errors. Errors must all have a message, and either the source
+// locations GraphQL errors carry or GraphQL wording, since REST APIs also answer with an "errors" key.
+func isGraphQLResponse(data []byte) bool {
+	var resp struct {
+		Data struct {
+			Schema json.RawMessage `json:"__schema"`
+		} `json:"data"`
+		Errors []map[string]json.RawMessage `json:"errors"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return false
+	}
+
+	if len(resp.Data.Schema) > 0 && string(resp.Data.Schema) != "null" {
+		return true
+	}
+
+	if len(resp.Errors) == 0 {
+		return false
+	}
+
+	graphql := false
+	for _, graphqlErr := range resp.Errors {
+		var message string
+		if err := json.Unmarshal(graphqlErr["message"], &message); err != nil || message == "" {
+			return false
+		}
+
+		_, hasLocations := graphqlErr["locations"]
+		if hasLocations || graphqlErrorRegex.MatchString(message) {
+			graphql = true
+		}
+	}
+
+	return graphql
+}
+
+func joinBasePath(basePath string, path string) string {
+	if basePath == "" || basePath == "/" {
+		return path
+	}
+
+	return strings.TrimSuffix(basePath, "/") + path
+}
+
+func sortOperations(operations []ApiOperation) {
+	sort.Slice(operations, func(i, j int) bool {
+		if operations[i].Path != operations[j].Path {
+			return operations[i].Path < operations[j].Path
+		}
+		return operations[i].Method < operations[j].Method
+	})
+}
+
+// Probes the host for OpenAPI/Swagger specifications and GraphQL endpoints
+func (crawler *Crawler) probeApis(host url.URL) {
+	for _, path := range apiSpecPaths {
+		specUrl := host
+		specUrl.Path = path
+
+		status, _, body, err := crawler.probeRequest(http.MethodGet, specUrl, "", nil)
+		if err != nil || status != http.StatusOK {
+			continue
+		}
+
+		operations, err := ParseApiSpec(body)
+		if err != nil {
+			continue
+		}
+
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.ApiSpec,
+			Value:  specUrl.String(),
+			Url:    specUrl,
+			Source: shared.ApiDiscovery,
+		})
+
+		for _, op := range operations {
+			crawler.handleHostFinding(shared.Finding{
+				Type:   shared.ApiOperation,
+				Value:  op.String(),
+				Url:    specUrl,
+				Source: shared.ApiDiscovery,
+			})
+		}
+	}
+
+	for _, path := range graphqlPaths {
+		endpoint := host
+		endpoint.Path = path
+
+		status, _, body, err := crawler.probeRequest(http.MethodPost, endpoint, "application/json", []byte(introspectionQuery))
+		// servers that do not accept the POST, or fail on it, say nothing about GraphQL
+		if err != nil || status == http.StatusNotFound || status == http.StatusMethodNotAllowed || status >= 500 {
+			continue
+		}
+
+		if !isGraphQLResponse(body) {
+			continue
+		}
+
+		schema, err := ParseIntrospection(body)
+		introspection := "introspection disabled"
+		if err == nil {
+			introspection = "introspection enabled"
+		}
+
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.GraphQLEndpoint,
+			Value:  endpoint.String() + " (" + introspection + ")",
+			Url:    endpoint,
+			Source: shared.ApiDiscovery,
+		})
+
+		for _, operation := range schema.Operations {
+			crawler.handleHostFinding(shared.Finding{
+				Type:   shared.GraphQLOperation,
+				Value:  operation,
+				Url:    endpoint,
+				Source: shared.ApiDiscovery,
+			})
+		}
+
+		for _, t := range schema.Types {
+			crawler.handleHostFinding(shared.Finding{
+				Type:   shared.GraphQLType,
+				Value:  t,
+				Url:    endpoint,
+				Source: shared.ApiDiscovery,
+			})
+		}
+	}
+}
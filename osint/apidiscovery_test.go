@@ -0,0 +1,142 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseApiSpecJson(t *testing.T) {
+	spec := []byte(`{
+		"swagger": "2.0",
+		"basePath": "/v1",
+		"paths": {
+			"/users": {"get": {}, "post": {}, "parameters": []},
+			"/users/{id}": {"delete": {}}
+		}
+	}`)
+
+	expected := []ApiOperation{
+		{"GET", "/v1/users"},
+		{"POST", "/v1/users"},
+		{"DELETE", "/v1/users/{id}"},
+	}
+
+	res, err := ParseApiSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseApiSpec expected %v; got %v", expected, res)
+	}
+
+	if _, err := ParseApiSpec([]byte(`{"paths": {}}`)); err == nil {
+		t.Errorf("ParseApiSpec expected error for document without version")
+	}
+}
+
+func TestParseApiSpecYaml(t *testing.T) {
+	spec := []byte(`openapi: 3.0.0
+info:
+  title: Example
+paths:
+  /pets:
+    get:
+      summary: List pets
+      responses:
+        '200':
+          description: ok
+    post:
+      summary: Create pet
+  "/pets/{petId}":
+    parameters:
+      - name: petId
+    get:
+      summary: Get pet
+components:
+  schemas:
+    Pet:
+      type: object
+`)
+
+	expected := []ApiOperation{
+		{"GET", "/pets"},
+		{"POST", "/pets"},
+		{"GET", "/pets/{petId}"},
+	}
+
+	res, err := ParseApiSpec(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseApiSpec expected %v; got %v", expected, res)
+	}
+
+	if _, err := ParseApiSpec([]byte("<html><body>Not found</body></html>")); err == nil {
+		t.Errorf("ParseApiSpec expected error for HTML document")
+	}
+}
+
+func TestParseIntrospection(t *testing.T) {
+	data := []byte(`{"data": {"__schema": {
+		"queryType": {"name": "Query"},
+		"mutationType": {"name": "Mutation"},
+		"subscriptionType": null,
+		"types": [
+			{"name": "Query", "kind": "OBJECT", "fields": [{"name": "users"}, {"name": "user"}]},
+			{"name": "Mutation", "kind": "OBJECT", "fields": [{"name": "createUser"}]},
+			{"name": "User", "kind": "OBJECT", "fields": [{"name": "id"}]},
+			{"name": "Role", "kind": "ENUM", "fields": null},
+			{"name": "String", "kind": "SCALAR", "fields": null},
+			{"name": "__Schema", "kind": "OBJECT", "fields": []}
+		]
+	}}}`)
+
+	expected := GraphQLSchema{
+		Operations: []string{"query users", "query user", "mutation createUser"},
+		Types:      []string{"User (OBJECT)", "Role (ENUM)"},
+	}
+
+	res, err := ParseIntrospection(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseIntrospection expected %v; got %v", expected, res)
+	}
+
+	disabled := []byte(`{"errors": [{"message": "introspection is disabled"}]}`)
+	if _, err := ParseIntrospection(disabled); err == nil {
+		t.Errorf("ParseIntrospection expected error when introspection is disabled")
+	}
+
+	if !isGraphQLResponse(disabled) {
+		t.Errorf("isGraphQLResponse expected error response to be identified as GraphQL")
+	}
+}
+
+func TestIsGraphQLResponse(t *testing.T) {
+	cases := []struct {
+		body     string
+		expected bool
+	}{
+		{`{"data": {"__schema": {"types": []}}}`, true},
+		{`{"errors": [{"message": "Cannot query field \"__schema\" on type \"Query\"."}]}`, true},
+		{`{"errors": [{"message": "Unexpected token", "locations": [{"line": 1, "column": 2}]}]}`, true},
+		// REST APIs that answer with an "errors" or "data" key
+		{`{"errors": [{"message": "Invalid request body"}]}`, false},
+		{`{"errors": ["method not allowed"]}`, false},
+		{`{"errors": {"body": "required"}}`, false},
+		{`{"data": {"users": []}}`, false},
+		{`not json`, false},
+	}
+
+	for _, c := range cases {
+		if res := isGraphQLResponse([]byte(c.body)); res != c.expected {
+			t.Errorf("isGraphQLResponse(%s) expected %v; got %v", c.body, c.expected, res)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package osint
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Suffixes appended to a file name by editors, deployment scripts and administrators
+var backupSuffixes = []string{".bak", ".old", ".orig", "~", ".save", ".tmp"}
+
+// Extensions that replace the file's own extension (e.g. config.php -> config.bak)
+var backupExtensions = []string{".bak", ".old"}
+
+// Extensions of archives of a whole directory
+var archiveExtensions = []string{".zip", ".tar.gz"}
+
+// Returns the common backup and variant paths of a file (e.g. /app/config.php.bak, /app/.config.php.swp,
+// /app/Copy of config.php and /app.zip). Paths without a file name are ignored.
+func BackupVariants(filePath string) []string {
+	dir, name := path.Split(filePath)
+	if name == "" || !strings.Contains(name, ".") {
+		return nil
+	}
+
+	var variants []string
+	for _, suffix := range backupSuffixes {
+		variants = append(variants, dir+name+suffix)
+	}
+
+	// vim swap files are hidden, but are sometimes copied around without the leading dot
+	variants = append(variants, dir+"."+name+".swp", dir+name+".swp")
+
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base != "" {
+		for _, backupExt := range backupExtensions {
+			variants = append(variants, dir+base+backupExt)
+		}
+
+		variants = append(variants, dir+base+" - Copy"+ext)
+	}
+
+	variants = append(variants, dir+"Copy of "+name, dir+"copy_of_"+name)
+
+	// archives of the directory are stored next to it, which the root directory does not have
+	if dir != "/" && dir != "" {
+		trimmed := strings.TrimSuffix(dir, "/")
+		for _, archiveExt := range archiveExtensions {
+			variants = append(variants, trimmed+archiveExt)
+		}
+	}
+
+	return variants
+}
+
+// Probes the backup variants of every in-scope file concurrently, respecting the crawler's thread count and
+// delay. Variants that look like the host's soft-404 pages are ignored, and hits are reported as findings.
+func (crawler *Crawler) ProbeBackups(files []url.URL) {
+	var targets []url.URL
+	for _, file := range files {
+		if !shared.SameBaseDomain(file.Hostname(), crawler.seedUrl.Hostname()) {
+			continue
+		}
+
+		for _, variant := range BackupVariants(file.Path) {
+			target := url.URL{Scheme: file.Scheme, Host: file.Host, Path: variant}
+
+			crawler.mutex.Lock()
+			_, exists := crawler.backupMap[target.String()]
+			crawler.backupMap[target.String()] = struct{}{}
+			crawler.mutex.Unlock()
+
+			if !exists {
+				targets = append(targets, target)
+			}
+		}
+	}
+
+	crawler.runConcurrently(len(targets), func(i int) {
+		crawler.probeBackup(targets[i])
+	})
+}
+
+func (crawler *Crawler) probeBackup(target url.URL) {
+	status, header, body, err := crawler.probeRequestWith(noRedirectClient, http.MethodGet, target, "", nil)
+	if err != nil || status != http.StatusOK || len(body) == 0 {
+		return
+	}
+
+	if crawler.isSoft404(target, status, body) {
+		return
+	}
+
+	contentType := header.Get("Content-Type")
+	if contentType == "" {
+		contentType = "unknown type"
+	}
+
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.BackupFile,
+		Value:  fmt.Sprintf("%s (%d bytes, %s)", target.String(), len(body), contentType),
+		Url:    target,
+		Source: shared.Backups,
+	})
+}
@@ -0,0 +1,58 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBackupVariants(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{
+			path: "/app/config.php",
+			expected: []string{
+				"/app/config.php.bak",
+				"/app/config.php.old",
+				"/app/config.php.orig",
+				"/app/config.php~",
+				"/app/config.php.save",
+				"/app/config.php.tmp",
+				"/app/.config.php.swp",
+				"/app/config.php.swp",
+				"/app/config.bak",
+				"/app/config.old",
+				"/app/config - Copy.php",
+				"/app/Copy of config.php",
+				"/app/copy_of_config.php",
+				"/app.zip",
+				"/app.tar.gz",
+			},
+		},
+		{
+			path: "/.htaccess",
+			expected: []string{
+				"/.htaccess.bak",
+				"/.htaccess.old",
+				"/.htaccess.orig",
+				"/.htaccess~",
+				"/.htaccess.save",
+				"/.htaccess.tmp",
+				"/..htaccess.swp",
+				"/.htaccess.swp",
+				"/Copy of .htaccess",
+				"/copy_of_.htaccess",
+			},
+		},
+		{path: "/app/", expected: nil},
+		{path: "/app/users", expected: nil},
+	}
+
+	for _, c := range cases {
+		res := BackupVariants(c.path)
+		if !reflect.DeepEqual(res, c.expected) {
+			t.Errorf("BackupVariants(%q) expected %v; got %v", c.path, c.expected, res)
+		}
+	}
+}
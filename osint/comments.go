@@ -0,0 +1,202 @@
+package osint
+
+import (
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Keywords used to filter comments when none are provided
+var DefaultCommentKeywords = []string{
+	"todo", "fixme", "hack", "bug", "xxx", "temp", "debug", "test", "deprecated", "remove",
+	"password", "passwd", "pwd", "secret", "token", "apikey", "api_key", "key", "credential",
+	"admin", "internal", "private", "backup", "config", "endpoint", "api", "staging", "dev",
+}
+
+var (
+	commentUrlRegex  = regexp.MustCompile(`https?://[^\s"'<>()\[\]{}]+`)
+	commentPathRegex = regexp.MustCompile(`(?:^|[\s"'=(:,])(/[A-Za-z0-9_\-.~%]+(?:/[A-Za-z0-9_\-.~%]*)*(?:\?[A-Za-z0-9_\-.~%=&]*)?)`)
+	whitespaceRegex  = regexp.MustCompile(`\s+`)
+)
+
+// Decides which comments are worth reporting
+type CommentFilter struct {
+	Keywords  []string
+	MinLength int
+}
+
+func NewCommentFilter(keywords []string, minLength int) CommentFilter {
+	if len(keywords) == 0 {
+		keywords = DefaultCommentKeywords
+	}
+
+	lowered := make([]string, len(keywords))
+	for i, keyword := range keywords {
+		lowered[i] = strings.ToLower(keyword)
+	}
+
+	return CommentFilter{
+		Keywords:  lowered,
+		MinLength: minLength,
+	}
+}
+
+// Checks if the comment is long enough and contains at least one of the keywords as a whole word
+func (filter *CommentFilter) IsInteresting(comment string) bool {
+	if len(comment) < filter.MinLength {
+		return false
+	}
+
+	for _, keyword := range filter.Keywords {
+		if containsWord(comment, keyword) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Checks if the lowercase keyword appears in the text as a whole word, optionally followed by an "s". Underscores,
+// hyphens and camelCase humps separate words, so DB_PASSWORD and dbPasswords contain "password" but "keyboard"
+// does not contain "key".
+func containsWord(text string, keyword string) bool {
+	if keyword == "" {
+		return false
+	}
+
+	lowered := strings.ToLower(text)
+
+	// case humps can only be found when lowering the text kept every byte in place
+	sameLength := len(lowered) == len(text)
+	isHump := func(i int) bool {
+		return sameLength && isLowerByte(text[i-1]) && text[i] >= 'A' && text[i] <= 'Z'
+	}
+
+	for offset := 0; offset < len(lowered); {
+		index := strings.Index(lowered[offset:], keyword)
+		if index == -1 {
+			return false
+		}
+
+		start := offset + index
+		end := start + len(keyword)
+		offset = start + 1
+
+		if start > 0 && isAlphanumeric(lowered[start-1]) && !isHump(start) {
+			continue
+		}
+
+		if end < len(lowered) && lowered[end] == 's' && (end+1 == len(lowered) || !isAlphanumeric(lowered[end+1])) {
+			end++
+		}
+
+		if end == len(lowered) || !isAlphanumeric(lowered[end]) || isHump(end) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isLowerByte(c byte) bool {
+	return c >= 'a' && c <= 'z'
+}
+
+func isAlphanumeric(c byte) bool {
+	return isLowerByte(c) || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// Collects HTML comments and the comments inside inline <script> tags, with whitespace collapsed
+func ExtractComments(node *html.Node) []string {
+	var comments []string
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.CommentNode:
+			comments = append(comments, node.Data)
+		case html.ElementNode:
+			if node.Data == "script" && node.FirstChild != nil && node.FirstChild.Type == html.TextNode {
+				comments = append(comments, extractJSComments(node.FirstChild.Data)...)
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(node)
+
+	return collapseComments(comments)
+}
+
+// Collects the comments of a JavaScript file, with whitespace collapsed
+func ExtractScriptComments(src string) []string {
+	return collapseComments(extractJSComments(src))
+}
+
+func collapseComments(comments []string) []string {
+	var output []string
+	for _, comment := range comments {
+		collapsed := strings.TrimSpace(whitespaceRegex.ReplaceAllString(comment, " "))
+		if collapsed != "" {
+			output = append(output, collapsed)
+		}
+	}
+
+	return output
+}
+
+// Returns the contents of // and /* */ comments in JavaScript source, ignoring string literals
+func extractJSComments(src string) []string {
+	var comments []string
+
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '"' || c == '\'' || c == '`':
+			// skip until the closing quote, taking escapes into account
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' {
+					i++
+				}
+			}
+		case c == '/' && i+1 < len(src) && src[i+1] == '/':
+			end := strings.IndexByte(src[i:], '\n')
+			if end == -1 {
+				end = len(src) - i
+			}
+
+			comments = append(comments, src[i+2:i+end])
+			i += end
+		case c == '/' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*/")
+			if end == -1 {
+				comments = append(comments, src[i+2:])
+				return comments
+			}
+
+			comments = append(comments, src[i+2:i+2+end])
+			i += end + 3
+		}
+	}
+
+	return comments
+}
+
+// Finds absolute URLs and absolute paths mentioned inside a comment
+func ExtractCommentUrls(comment string) []string {
+	var urls []string
+	for _, match := range commentUrlRegex.FindAllString(comment, -1) {
+		urls = append(urls, strings.TrimRight(match, ".,;:!"))
+	}
+
+	// remove absolute URLs before looking for paths to avoid matching their paths twice
+	stripped := commentUrlRegex.ReplaceAllString(comment, " ")
+	for _, match := range commentPathRegex.FindAllStringSubmatch(stripped, -1) {
+		urls = append(urls, strings.TrimRight(match[1], ".,;:!"))
+	}
+
+	return urls
+}
@@ -0,0 +1,128 @@
+package osint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+func TestExtractComments(t *testing.T) {
+	page := `<html><head>
+		<!-- TODO: remove   /old-admin before release -->
+		<script>
+			var s = "not // a comment";
+			// uses https://staging.example.com/api
+			/* multi
+			   line */
+		</script>
+	</head><body><!----></body></html>`
+
+	node, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"TODO: remove /old-admin before release",
+		"uses https://staging.example.com/api",
+		"multi line",
+	}
+
+	res := ExtractComments(node)
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ExtractComments expected %q; got %q", expected, res)
+	}
+}
+
+func TestExtractCommentUrls(t *testing.T) {
+	cases := []struct {
+		comment  string
+		expected []string
+	}{
+		{"TODO: remove /old-admin before release", []string{"/old-admin"}},
+		{"see https://staging.example.com/api/v1.", []string{"https://staging.example.com/api/v1"}},
+		{`<a href="/backup/db.sql?v=2">`, []string{"/backup/db.sql?v=2"}},
+		{"and/or 1/2", nil},
+	}
+
+	for _, tc := range cases {
+		res := ExtractCommentUrls(tc.comment)
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("ExtractCommentUrls(%s) expected %q; got %q", tc.comment, tc.expected, res)
+		}
+	}
+}
+
+func TestCommentFilter(t *testing.T) {
+	filter := NewCommentFilter([]string{"Password"}, 10)
+
+	cases := map[string]bool{
+		"password":                 false,
+		"default password: admin":  true,
+		"nothing interesting here": false,
+	}
+
+	for comment, expected := range cases {
+		if res := filter.IsInteresting(comment); res != expected {
+			t.Errorf("IsInteresting(%s) expected %v; got %v", comment, expected, res)
+		}
+	}
+
+	// keywords must be whole words, so ordinary words containing them are not reported
+	filter = NewCommentFilter(nil, 0)
+
+	cases = map[string]bool{
+		"TODO: drop the old endpoint":       true,
+		"export DB_PASSWORD before running": true,
+		"const dbPasswords = load()":        true,
+		"keys are rotated weekly":           true,
+		"show the latest device list":       false,
+		"keyboard shortcuts":                false,
+		"attempt the contest again":         false,
+	}
+
+	for comment, expected := range cases {
+		if res := filter.IsInteresting(comment); res != expected {
+			t.Errorf("IsInteresting(%s) expected %v; got %v", comment, expected, res)
+		}
+	}
+}
+
+func TestScriptComments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><body><script src="/static/app.js"></script><script src="https://cdn.example.net/lib.js"></script></body></html>`))
+		case "/static/app.js":
+			w.Write([]byte("var url = \"// not a comment\";\n// TODO: remove the debug token before release\nfetch(url);"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	seed, _ := url.Parse(server.URL)
+	comms := shared.NewCommsChannels()
+
+	crawler := NewCrawler(false, true, *seed, 2, 0, []url.URL{*seed}, 1, comms, map[string]string{}, map[string]string{})
+	crawler.EnableCommentHarvest(NewCommentFilter(nil, 10))
+
+	var comments []string
+	for _, finding := range collectCrawlFindings(&crawler, comms) {
+		if finding.Type == shared.Comment {
+			comments = append(comments, finding.Url.Path+" "+finding.Value)
+		}
+	}
+
+	// out-of-scope scripts are not fetched
+	expected := []string{"/static/app.js TODO: remove the debug token before release"}
+	if !reflect.DeepEqual(comments, expected) {
+		t.Errorf("Crawl expected the comments of the external script %v; got %v", expected, comments)
+	}
+}
@@ -0,0 +1,223 @@
+package osint
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+var (
+	emailRegex = regexp.MustCompile(`(?i)[a-z0-9._%+\-]+@[a-z0-9\-]+(?:\.[a-z0-9\-]+)*\.[a-z]{2,}`)
+
+	// matches obfuscated emails such as "name [at] domain [dot] com" or "name(at)domain.com"
+	obfuscatedEmailRegex = regexp.MustCompile(
+		`(?i)([a-z0-9._%+\-]+)\s*[\[\(\{]\s*at\s*[\]\)\}]\s*([a-z0-9\-]+(?:\s*(?:[\[\(\{]\s*dot\s*[\]\)\}]|\.)\s*[a-z0-9\-]+)+)`,
+	)
+	obfuscatedDotRegex = regexp.MustCompile(`(?i)\s*[\[\(\{]\s*dot\s*[\]\)\}]\s*`)
+
+	phoneRegex = regexp.MustCompile(`\+?\(?\d[\d\s().\-]{7,}\d`)
+)
+
+// Hosts considered social media platforms, and the paths that are not user profiles
+var socialHosts = map[string][]string{
+	"twitter.com":   {"/intent", "/share", "/home"},
+	"x.com":         {"/intent", "/share", "/home"},
+	"facebook.com":  {"/sharer", "/share", "/dialog", "/plugins"},
+	"instagram.com": {"/p/", "/explore"},
+	"linkedin.com":  {"/sharing", "/shareArticle"},
+	"github.com":    {"/login", "/features"},
+	"gitlab.com":    {"/users/sign_in"},
+	"youtube.com":   {"/watch", "/embed", "/results"},
+	"tiktok.com":    {"/embed"},
+	"t.me":          {"/share"},
+	"reddit.com":    {"/submit"},
+	"pinterest.com": {"/pin/create"},
+}
+
+type Contact struct {
+	Type  shared.FindingType
+	Value string
+}
+
+// Extracts emails, phone numbers and social media profile links from a page.
+// Emails are tagged as on-domain if they share the same registrable domain as targetHost.
+func ExtractContacts(node *html.Node, targetHost string) []Contact {
+	seen := map[string]struct{}{}
+	var contacts []Contact
+
+	add := func(contactType shared.FindingType, value string) {
+		if _, exists := seen[value]; exists {
+			return
+		}
+
+		seen[value] = struct{}{}
+		contacts = append(contacts, Contact{Type: contactType, Value: value})
+	}
+
+	addEmail := func(email string) {
+		email = strings.ToLower(strings.Trim(email, "."))
+		_, domain, found := strings.Cut(email, "@")
+		if !found {
+			return
+		}
+
+		if shared.SameBaseDomain(domain, targetHost) {
+			add(shared.EmailOnDomain, email)
+		} else {
+			add(shared.EmailOffDomain, email)
+		}
+	}
+
+	var text strings.Builder
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		switch node.Type {
+		case html.TextNode:
+			text.WriteString(node.Data)
+			text.WriteString(" ")
+		case html.ElementNode:
+			// script and style contents are not visible text
+			if node.Data == "script" || node.Data == "style" {
+				return
+			}
+
+			if node.Data == "a" {
+				for _, attr := range node.Attr {
+					if attr.Key != "href" {
+						continue
+					}
+
+					switch {
+					case strings.HasPrefix(strings.ToLower(attr.Val), "mailto:"):
+						addr, _, _ := strings.Cut(attr.Val[len("mailto:"):], "?")
+						if unescaped, err := url.PathUnescape(addr); err == nil {
+							addr = unescaped
+						}
+						for _, email := range strings.Split(addr, ",") {
+							addEmail(strings.TrimSpace(email))
+						}
+					case strings.HasPrefix(strings.ToLower(attr.Val), "tel:"):
+						if phone, ok := normalizePhone(attr.Val[len("tel:"):]); ok {
+							add(shared.Phone, phone)
+						}
+					default:
+						if profile, ok := parseSocialProfile(attr.Val); ok {
+							add(shared.SocialProfile, profile)
+						}
+					}
+				}
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(node)
+
+	content := text.String()
+	for _, email := range emailRegex.FindAllString(content, -1) {
+		addEmail(email)
+	}
+
+	for _, match := range obfuscatedEmailRegex.FindAllStringSubmatch(content, -1) {
+		domain := obfuscatedDotRegex.ReplaceAllString(match[2], ".")
+		addEmail(match[1] + "@" + strings.ReplaceAll(domain, " ", ""))
+	}
+
+	for _, candidate := range phoneRegex.FindAllString(content, -1) {
+		if !isGroupedPhone(candidate) {
+			continue
+		}
+
+		if phone, ok := normalizePhone(candidate); ok {
+			add(shared.Phone, phone)
+		}
+	}
+
+	return contacts
+}
+
+// Strips formatting characters from a phone number and validates its length against E.164
+func normalizePhone(str string) (string, bool) {
+	str = strings.TrimSpace(str)
+
+	var digits strings.Builder
+	for i, r := range str {
+		switch {
+		case r >= '0' && r <= '9':
+			digits.WriteRune(r)
+		case r == '+' && i == 0:
+			digits.WriteRune(r)
+		case strings.ContainsRune(" ().-", r):
+			continue
+		default:
+			return "", false
+		}
+	}
+
+	phone := digits.String()
+	count := len(strings.TrimPrefix(phone, "+"))
+
+	// shorter sequences are usually dates, prices, or IDs
+	if count < 9 || count > 15 {
+		return "", false
+	}
+
+	return phone, true
+}
+
+// Reports whether a number found in the page text is written like a phone number, either in international format
+// or split into groups ending in four digits. Bare digit runs are usually order numbers, timestamps, or tracking IDs,
+// and groups of three are usually prices or IP addresses.
+func isGroupedPhone(candidate string) bool {
+	if strings.HasPrefix(candidate, "+") {
+		return true
+	}
+
+	groups := strings.FieldsFunc(candidate, func(r rune) bool { return r < '0' || r > '9' })
+	if len(groups) < 3 || len(groups[len(groups)-1]) != 4 {
+		return false
+	}
+
+	for _, group := range groups {
+		if len(group) > 4 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the normalized profile URL if the link points to a social media profile
+func parseSocialProfile(link string) (string, bool) {
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return "", false
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	host = strings.TrimPrefix(host, "m.")
+
+	excluded, exists := socialHosts[host]
+	if !exists {
+		return "", false
+	}
+
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "" {
+		return "", false
+	}
+
+	for _, prefix := range excluded {
+		if strings.HasPrefix(path, prefix) {
+			return "", false
+		}
+	}
+
+	return "https://" + host + path, true
+}
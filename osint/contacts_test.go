@@ -0,0 +1,93 @@
+package osint
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+func TestExtractContacts(t *testing.T) {
+	page := `<html><body>
+		<p>Contact us at info@example.com or sales [at] example [dot] com</p>
+		<p>Press: press(at)agency.org</p>
+		<a href="mailto:Support@Example.com?subject=hi">mail</a>
+		<a href="tel:+1 (555) 123-4567">call</a>
+		<p>Office: +44 20 7946 0958 - Copyright 2024</p>
+		<p>Support: (555) 987-6543</p>
+		<p>Order 123456789012 placed at 1700000000123, tracking 9400 1000 0000 0000 0000 00</p>
+		<p>Total: 1 234 567 890, server 192.168.100.200, updated 2024-01-01 12</p>
+		<a href="tel:5551112222">call</a>
+		<a href="https://twitter.com/example">twitter</a>
+		<a href="https://twitter.com/intent/tweet?text=hi">share</a>
+		<a href="https://www.linkedin.com/company/example/">linkedin</a>
+		<script>var x = "hidden@example.com";</script>
+	</body></html>`
+
+	node, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Contact{
+		{shared.EmailOnDomain, "support@example.com"},
+		{shared.Phone, "+15551234567"},
+		{shared.Phone, "5551112222"},
+		{shared.SocialProfile, "https://twitter.com/example"},
+		{shared.SocialProfile, "https://linkedin.com/company/example"},
+		{shared.EmailOnDomain, "info@example.com"},
+		{shared.EmailOnDomain, "sales@example.com"},
+		{shared.EmailOffDomain, "press@agency.org"},
+		{shared.Phone, "+442079460958"},
+		{shared.Phone, "5559876543"},
+	}
+
+	res := ExtractContacts(node, "www.example.com")
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ExtractContacts expected %v; got %v", expected, res)
+	}
+}
+
+func TestIsGroupedPhone(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected bool
+	}{
+		{"+15551234567", true},
+		{"(555) 123-4567", true},
+		{"020 7946 0958", true},
+		{"123456789012", false},
+		{"1 234 567 890", false},
+		{"192.168.100.200", false},
+		{"2024-01-01 12", false},
+		{"9400 1000 0000 0000 0000 00", false},
+	}
+
+	for _, tc := range cases {
+		if res := isGroupedPhone(tc.input); res != tc.expected {
+			t.Errorf("isGroupedPhone(%s) expected %v; got %v", tc.input, tc.expected, res)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected string
+		ok       bool
+	}{
+		{"+1 (555) 123-4567", "+15551234567", true},
+		{"020.7946.0958", "02079460958", true},
+		{"2023-2024", "", false},
+		{"12345a6789", "", false},
+	}
+
+	for _, tc := range cases {
+		res, ok := normalizePhone(tc.input)
+		if ok != tc.ok || res != tc.expected {
+			t.Errorf("normalizePhone(%s) expected %s, %v; got %s, %v", tc.input, tc.expected, tc.ok, res, ok)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package osint
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	emptyWordlistErr = "wordlist contains no words"
+)
+
+// Decides which content discovery responses are reported. A response is reported if its status code is in
+// MatchStatus (any status if empty), and neither its size nor its word count are filtered out.
+type ResponseFilter struct {
+	MatchStatus []int
+	FilterSizes []int
+	FilterWords []int
+}
+
+// Checks if a response with the given status code, size and word count passes the filter
+func (filter ResponseFilter) Matches(status int, size int, words int) bool {
+	if len(filter.MatchStatus) > 0 && !shared.SliceContainsInt(filter.MatchStatus, status) {
+		return false
+	}
+
+	return !shared.SliceContainsInt(filter.FilterSizes, size) && !shared.SliceContainsInt(filter.FilterWords, words)
+}
+
+// Brute forces paths from a wordlist against directories
+type ContentDiscovery struct {
+	words        []string
+	extensions   []string
+	filter       ResponseFilter
+	maxRecursion int
+}
+
+// Path to be requested in a directory
+type discoveryJob struct {
+	dir       url.URL
+	candidate string
+	depth     int
+}
+
+// Loads a wordlist with one word per line, ignoring empty lines and comments
+func LoadWordlist(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+
+		words = append(words, strings.TrimPrefix(word, "/"))
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf(emptyWordlistErr)
+	}
+
+	return words, nil
+}
+
+// Creates a content discovery module. Each word is also tried with every extension (e.g. "php" or ".php"), and
+// directories found by it are brute forced up to maxRecursion levels deep.
+func NewContentDiscovery(words []string, extensions []string, filter ResponseFilter, maxRecursion int) ContentDiscovery {
+	var normalized []string
+	for _, ext := range extensions {
+		ext = strings.TrimPrefix(strings.TrimSpace(ext), ".")
+		if ext != "" {
+			normalized = append(normalized, ext)
+		}
+	}
+
+	return ContentDiscovery{
+		words:        words,
+		extensions:   normalized,
+		filter:       filter,
+		maxRecursion: maxRecursion,
+	}
+}
+
+// Returns every path to be requested in a directory. Extensions are not added to words ending with a slash.
+func (cd *ContentDiscovery) Candidates() []string {
+	var candidates []string
+	for _, word := range cd.words {
+		candidates = append(candidates, word)
+		if strings.HasSuffix(word, "/") {
+			continue
+		}
+
+		for _, ext := range cd.extensions {
+			candidates = append(candidates, word+"."+ext)
+		}
+	}
+
+	return candidates
+}
+
+// Returns the directory a redirect points to if it only adds a trailing slash to the requested path
+func redirectedDir(target url.URL, header http.Header) (url.URL, bool) {
+	location, err := url.Parse(header.Get("Location"))
+	if err != nil {
+		return url.URL{}, false
+	}
+
+	resolved := *target.ResolveReference(location)
+	if resolved.Host != target.Host || resolved.Path != target.Path+"/" {
+		return url.URL{}, false
+	}
+
+	resolved.RawQuery = ""
+	return resolved, true
+}
+
+// Queues the directory to be brute forced once the other directory probes are done
+func (crawler *Crawler) queueContentDiscovery(dir url.URL) {
+	crawler.mutex.Lock()
+	defer crawler.mutex.Unlock()
+
+	crawler.contentQueue = append(crawler.contentQueue, dir)
+}
+
+// Brute forces the queued directories until none are left or maxRecursion levels were brute forced, probing the
+// directories found along the way, so recursion does not depend on the crawl depth
+func (crawler *Crawler) drainContentQueue() {
+	for level := 0; level <= crawler.contentDiscovery.maxRecursion; level++ {
+		crawler.mutex.Lock()
+		pending := len(crawler.contentQueue)
+		crawler.mutex.Unlock()
+
+		if pending == 0 {
+			return
+		}
+
+		crawler.discoverQueuedContent()
+		crawler.probeQueuedDirs()
+	}
+}
+
+// Brute forces every queued directory. Each directory is calibrated first, and then every candidate path of
+// every directory is requested concurrently, respecting the crawler's thread count and delay.
+func (crawler *Crawler) discoverQueuedContent() {
+	crawler.mutex.Lock()
+	queue := crawler.contentQueue
+	crawler.contentQueue = []url.URL{}
+	crawler.mutex.Unlock()
+
+	var jobs []discoveryJob
+	for _, dir := range queue {
+		crawler.mutex.Lock()
+		depth := crawler.discoveredDirs[dir.String()]
+		crawler.mutex.Unlock()
+
+		if depth > crawler.contentDiscovery.maxRecursion {
+			continue
+		}
+
+		for _, candidate := range crawler.contentDiscovery.Candidates() {
+			jobs = append(jobs, discoveryJob{dir: dir, candidate: candidate, depth: depth})
+		}
+	}
+
+	// directories are calibrated before their paths are requested, so the workers do not all wait on it
+	crawler.runConcurrently(len(queue), func(i int) {
+		crawler.dirFingerprints(queue[i])
+	})
+
+	crawler.runConcurrently(len(jobs), func(i int) {
+		crawler.discoverPath(jobs[i])
+	})
+}
+
+// Requests a single candidate path, and reports it and feeds it to the frontier if it passes the filter and
+// does not look like the responses to random paths in the directory
+func (crawler *Crawler) discoverPath(job discoveryJob) {
+	target := resolveDirFile(job.dir, job.candidate)
+
+	status, header, body, err := crawler.probeRequestWith(noRedirectClient, http.MethodGet, target, "", nil)
+	if err != nil {
+		return
+	}
+
+	size, words := len(body), len(strings.Fields(string(body)))
+	if !crawler.contentDiscovery.filter.Matches(status, size, words) {
+		return
+	}
+
+	if crawler.matchesRandomPaths(job.dir, NewPageFingerprint(status, body, target.Path)) {
+		return
+	}
+
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.DiscoveredContent,
+		Value:  fmt.Sprintf("%s (status %d, %d bytes, %d words)", target.Path, status, size, words),
+		Url:    job.dir,
+		Source: shared.ContentDiscovery,
+	})
+
+	found := target
+	if dir, ok := redirectedDir(target, header); ok {
+		found = dir
+	}
+
+	// directories are brute forced one level deeper than the one they were found in, and probed right away
+	// instead of waiting for the crawler to reach them
+	if strings.HasSuffix(found.Path, "/") {
+		crawler.mutex.Lock()
+		if _, exists := crawler.discoveredDirs[found.String()]; !exists {
+			crawler.discoveredDirs[found.String()] = job.depth + 1
+			if job.depth+1 <= crawler.contentDiscovery.maxRecursion {
+				crawler.queueDirs(found)
+			}
+		}
+		crawler.mutex.Unlock()
+	}
+
+	crawler.handleFoundUrl(found.String(), job.dir.Host, job.dir.Scheme)
+}
+
+// Calls fn for every index from 0 to n with a pool of workers the size of the crawler's thread count, since
+// wordlists can produce far more jobs than goroutines are worth spawning
+func (crawler *Crawler) runConcurrently(n int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < crawler.threads; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+}
@@ -0,0 +1,174 @@
+package osint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+func TestLoadWordlist(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wordlist.txt")
+	if err := os.WriteFile(path, []byte("# common paths\nadmin\n\n/backup\n  .env  \nuploads/\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"admin", "backup", ".env", "uploads/"}
+
+	res, err := LoadWordlist(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("LoadWordlist expected %v; got %v", expected, res)
+	}
+
+	empty := filepath.Join(t.TempDir(), "empty.txt")
+	os.WriteFile(empty, []byte("# nothing\n"), 0644)
+	if _, err := LoadWordlist(empty); err == nil {
+		t.Errorf("LoadWordlist expected error for wordlist without words")
+	}
+}
+
+func TestCandidates(t *testing.T) {
+	cd := NewContentDiscovery([]string{"admin", "uploads/"}, []string{".php", " bak", ""}, ResponseFilter{}, 1)
+	expected := []string{"admin", "admin.php", "admin.bak", "uploads/"}
+
+	res := cd.Candidates()
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("Candidates expected %v; got %v", expected, res)
+	}
+}
+
+func TestResponseFilterMatches(t *testing.T) {
+	filter := ResponseFilter{
+		MatchStatus: []int{200, 403},
+		FilterSizes: []int{1234},
+		FilterWords: []int{7},
+	}
+
+	cases := []struct {
+		status   int
+		size     int
+		words    int
+		expected bool
+	}{
+		{200, 500, 50, true},
+		{403, 10, 2, true},
+		{404, 500, 50, false},
+		{200, 1234, 50, false},
+		{200, 500, 7, false},
+	}
+
+	for _, c := range cases {
+		res := filter.Matches(c.status, c.size, c.words)
+		if res != c.expected {
+			t.Errorf("Matches(%d, %d, %d) expected %v; got %v", c.status, c.size, c.words, c.expected, res)
+		}
+	}
+
+	if !(ResponseFilter{}).Matches(500, 0, 0) {
+		t.Errorf("Matches expected empty filter to match any response")
+	}
+}
+
+func TestRedirectedDir(t *testing.T) {
+	target, _ := url.Parse("https://example.com/app/admin")
+
+	cases := []struct {
+		location string
+		expected string
+		ok       bool
+	}{
+		{"/app/admin/", "https://example.com/app/admin/", true},
+		{"admin/?lang=en", "https://example.com/app/admin/", true},
+		{"/login", "", false},
+		{"https://other.com/app/admin/", "", false},
+	}
+
+	for _, c := range cases {
+		res, ok := redirectedDir(*target, http.Header{"Location": {c.location}})
+		if ok != c.ok || (ok && res.String() != c.expected) {
+			t.Errorf("redirectedDir(%q) expected %q, %v; got %q, %v", c.location, c.expected, c.ok, res.String(), ok)
+		}
+	}
+}
+
+func TestContentDiscoveryRecursion(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte("<html><body>home</body></html>"))
+		case "/admin/":
+			w.Write([]byte("<html><body>admin</body></html>"))
+		case "/admin/panel/":
+			w.Write([]byte("<html><body>panel</body></html>"))
+		case "/admin/panel/logs/":
+			w.Write([]byte("<html><body>logs</body></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	seed, _ := url.Parse(server.URL)
+	comms := shared.NewCommsChannels()
+
+	// directories found by content discovery are brute forced even though the crawl stops after the seed
+	crawler := NewCrawler(false, true, *seed, 2, 0, []url.URL{*seed}, 1, comms, map[string]string{}, map[string]string{})
+	crawler.EnableContentDiscovery(NewContentDiscovery([]string{"admin/", "panel/", "logs/"}, nil, ResponseFilter{}, 1))
+
+	var paths []string
+	for _, finding := range collectCrawlFindings(&crawler, comms) {
+		if finding.Type == shared.DiscoveredContent {
+			paths = append(paths, strings.Fields(finding.Value)[0])
+		}
+	}
+
+	expected := []string{"/admin/", "/admin/panel/"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Crawl expected content discovered up to 1 level of recursion %v; got %v", expected, paths)
+	}
+}
+
+func TestContentDiscoveryCatchAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte("<html><head><title>Home</title></head><body>home</body></html>"))
+		case "/login":
+			w.Write([]byte("<html><head><title>Login</title></head><body>sign in</body></html>"))
+		case "/backup.zip":
+			w.Write([]byte("PK archive contents"))
+		default:
+			http.Redirect(w, r, "/login?next="+r.URL.Path, http.StatusFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	seed, _ := url.Parse(server.URL)
+	comms := shared.NewCommsChannels()
+
+	crawler := NewCrawler(false, true, *seed, 2, 0, []url.URL{*seed}, 1, comms, map[string]string{}, map[string]string{})
+	crawler.EnableContentDiscovery(NewContentDiscovery([]string{"admin", "backup"}, []string{"zip"}, ResponseFilter{}, 0))
+
+	var paths []string
+	for _, finding := range collectCrawlFindings(&crawler, comms) {
+		if finding.Type == shared.DiscoveredContent {
+			paths = append(paths, strings.Fields(finding.Value)[0])
+		}
+	}
+
+	// every other path redirects to the login page, like the random paths the directory is calibrated with
+	expected := []string{"/backup.zip"}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("Crawl expected only %v to be discovered; got %v", expected, paths)
+	}
+}
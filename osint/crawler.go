@@ -1,7 +1,10 @@
 package osint
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"strings"
@@ -17,19 +20,97 @@ import (
 
 const CRAWLER_NAME = "CRAWLER"
 
+// Maximum amount of bytes read from a single response
+const maxBodySize = 10 * 1024 * 1024
+
+// Client used by probes that need to see redirects instead of following them
+var noRedirectClient = &http.Client{
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// Runs once for every in-scope host reached by the crawler. The host URL only has its scheme and host set.
+type hostProbe func(host url.URL)
+
+// Runs once for every directory of an in-scope host reached by the crawler, including the root.
+// The directory URL only has its scheme, host and path set, and the path ends with a slash.
+type dirProbe func(dir url.URL)
+
+// Represents a fetched page along with the response data used by the page analyzers
+type page struct {
+	url        url.URL
+	statusCode int
+	header     http.Header
+	body       []byte
+	node       *html.Node
+
+	// real-time connections opened by the page, only captured in headless mode
+	websockets   []string
+	eventSources []string
+}
+
 type Crawler struct {
-	mutex    sync.Mutex
-	headless bool
-	lockHost bool
-	seedUrl  url.URL
-	maxDepth int
-	threads  int
-	delay    int
-	toCrawl  []url.URL
-	urlMap   map[string]url.URL
-	comms    shared.CommsChannels
-	cookies  map[string]string
-	headers  map[string]string
+	mutex      sync.Mutex
+	headless   bool
+	lockHost   bool
+	seedUrl    url.URL
+	maxDepth   int
+	threads    int
+	delay      int
+	toCrawl    []url.URL
+	urlMap     map[string]url.URL
+	findingMap map[string]struct{}
+	comms      shared.CommsChannels
+	cookies    map[string]string
+	headers    map[string]string
+	contacts   bool
+	comments   *CommentFilter
+
+	fingerprinter *Fingerprinter
+
+	favicons   bool
+	iconQueue  []iconRef
+	iconMap    map[string]struct{}
+	iconHashes map[string]*iconHash
+
+	headerAudit  bool
+	headerAudits map[string]HeaderAudit
+
+	sourceMaps   bool
+	sourceMapDir string
+	scriptQueue  []scriptRef
+	scriptMap    map[string]struct{}
+
+	hostProbes []hostProbe
+	hostQueue  []url.URL
+	hostMap    map[string]struct{}
+
+	dirProbes []dirProbe
+	dirQueue  []url.URL
+	dirMap    map[string]struct{}
+
+	contentDiscovery *ContentDiscovery
+	contentQueue     []url.URL
+	discoveredDirs   map[string]int
+
+	realtime      bool
+	realtimeQueue []realtimeRef
+	realtimeMap   map[string]struct{}
+
+	// scripts searched for real-time endpoints and comments
+	inScopeScripts   []scriptRef
+	inScopeScriptMap map[string]struct{}
+
+	soft404    bool
+	soft404Map map[string]*soft404Calibration
+
+	backupMap map[string]struct{}
+
+	dirListings   bool
+	listingBudget int
+	listingQueue  []url.URL
+	listingMap    map[string]struct{}
 }
 
 func NewCrawler(
@@ -45,19 +126,125 @@ func NewCrawler(
 	cookies map[string]string,
 ) Crawler {
 	return Crawler{
-		mutex:    sync.Mutex{},
-		headless: headless,
-		lockHost: lockHost,
-		seedUrl:  seedUrl,
-		threads:  threads,
-		delay:    delay,
-		maxDepth: maxDepth,
-		toCrawl:  toCrawl,
-		urlMap:   map[string]url.URL{},
-		comms:    comms,
-		cookies:  cookies,
-		headers:  headers,
+		mutex:      sync.Mutex{},
+		headless:   headless,
+		lockHost:   lockHost,
+		seedUrl:    seedUrl,
+		threads:    threads,
+		delay:      delay,
+		maxDepth:   maxDepth,
+		toCrawl:    toCrawl,
+		urlMap:     map[string]url.URL{},
+		findingMap: map[string]struct{}{},
+		comms:      comms,
+		cookies:    cookies,
+		headers:    headers,
+
+		iconMap:      map[string]struct{}{},
+		iconHashes:   map[string]*iconHash{},
+		headerAudits: map[string]HeaderAudit{},
+		scriptMap:    map[string]struct{}{},
+		hostMap:      map[string]struct{}{},
+		dirMap:       map[string]struct{}{},
+
+		discoveredDirs: map[string]int{},
+		soft404Map:     map[string]*soft404Calibration{},
+		backupMap:      map[string]struct{}{},
+		listingMap:     map[string]struct{}{},
+		realtimeMap:    map[string]struct{}{},
+
+		inScopeScriptMap: map[string]struct{}{},
+	}
+}
+
+// Enables the extraction of emails, phone numbers and social media profiles from every crawled page
+func (crawler *Crawler) EnableContactExtraction() {
+	crawler.contacts = true
+}
+
+// Enables the harvesting of HTML comments and the comments of inline and in-scope external scripts. URLs found in
+// comments are always crawled, but only the comments that pass the filter are reported.
+func (crawler *Crawler) EnableCommentHarvest(filter CommentFilter) {
+	crawler.comments = &filter
+}
+
+// Enables technology fingerprinting of every crawled page
+func (crawler *Crawler) EnableFingerprinting(fingerprinter Fingerprinter) {
+	crawler.fingerprinter = &fingerprinter
+}
+
+// Enables the passive audit of security headers, CORS policy and cookie flags of every crawled page
+func (crawler *Crawler) EnableHeaderAudit() {
+	crawler.headerAudit = true
+}
+
+// Returns the header audits performed during the crawl, one per host and path pattern
+func (crawler *Crawler) HeaderAudits() []HeaderAudit {
+	crawler.mutex.Lock()
+	defer crawler.mutex.Unlock()
+
+	audits := make([]HeaderAudit, 0, len(crawler.headerAudits))
+	for _, audit := range crawler.headerAudits {
+		audits = append(audits, audit)
 	}
+
+	return audits
+}
+
+// Enables probing every script seen by the crawler for a source map. Endpoints found in the original sources
+// are crawled, and the original sources are written to outputDir if it is not empty.
+func (crawler *Crawler) EnableSourceMapRecovery(outputDir string) {
+	crawler.sourceMaps = true
+	crawler.sourceMapDir = outputDir
+}
+
+// Enables probing every in-scope host for OpenAPI/Swagger specifications and GraphQL endpoints
+func (crawler *Crawler) EnableApiDiscovery() {
+	crawler.hostProbes = append(crawler.hostProbes, crawler.probeApis)
+}
+
+// Enables probing every in-scope host for well-known files (e.g. security.txt, openid-configuration,
+// crossdomain.xml). The URLs and domains listed in them are crawled.
+func (crawler *Crawler) EnableWellKnownProbing() {
+	crawler.hostProbes = append(crawler.hostProbes, crawler.probeWellKnown)
+}
+
+// Enables probing every in-scope host and directory for exposed .git, .svn and .hg repositories and .DS_Store
+// files. The paths listed in them are crawled.
+func (crawler *Crawler) EnableVcsProbing() {
+	crawler.dirProbes = append(crawler.dirProbes, crawler.probeVcs)
+}
+
+// Enables brute forcing every in-scope host and directory with the paths of a wordlist. The paths found are
+// reported and crawled.
+func (crawler *Crawler) EnableContentDiscovery(cd ContentDiscovery) {
+	crawler.contentDiscovery = &cd
+	crawler.dirProbes = append(crawler.dirProbes, crawler.queueContentDiscovery)
+}
+
+// Enables the discovery of WebSocket and Server-Sent Events endpoints. Endpoints on in-scope hosts are
+// verified with a handshake.
+func (crawler *Crawler) EnableRealtimeDiscovery() {
+	crawler.realtime = true
+}
+
+// Enables the detection of soft-404 pages, which are compared against the responses to random paths in their
+// directory. Probable soft-404s are reported and not expanded.
+func (crawler *Crawler) EnableSoft404Detection() {
+	crawler.soft404 = true
+}
+
+// Enables the detection of Apache, nginx and IIS directory listings. Listings are not expanded by the crawler;
+// their entries are reported instead, and their subdirectories are walked regardless of the crawl depth until
+// budget listings have been fetched.
+func (crawler *Crawler) EnableDirectoryListingWalk(budget int) {
+	crawler.dirListings = true
+	crawler.listingBudget = budget
+}
+
+// Enables fetching and hashing the favicon of every discovered host, as well as the icons declared by pages
+func (crawler *Crawler) EnableFaviconHashing() {
+	crawler.favicons = true
 }
 
 func (crawler *Crawler) Crawl(currDepth int) {
@@ -81,6 +268,38 @@ func (crawler *Crawler) Crawl(currDepth int) {
 
 	wg.Wait()
 
+	if crawler.favicons {
+		crawler.fetchQueuedFavicons()
+	}
+
+	if crawler.sourceMaps {
+		crawler.fetchQueuedSourceMaps()
+	}
+
+	if len(crawler.hostProbes) > 0 {
+		crawler.probeQueuedHosts()
+	}
+
+	if len(crawler.dirProbes) > 0 {
+		crawler.probeQueuedDirs()
+	}
+
+	if crawler.contentDiscovery != nil {
+		crawler.drainContentQueue()
+	}
+
+	if crawler.dirListings {
+		crawler.walkQueuedListings()
+	}
+
+	if crawler.realtime || crawler.comments != nil {
+		crawler.searchQueuedScripts()
+	}
+
+	if crawler.realtime {
+		crawler.verifyQueuedRealtimeEndpoints()
+	}
+
 	crawler.Crawl(currDepth + 1)
 }
 
@@ -90,44 +309,250 @@ func (crawler *Crawler) crawlSinglePage(url url.URL, wg *sync.WaitGroup, semapho
 
 	semaphore <- struct{}{}
 
-	var htmlNode *html.Node
+	var pg page
 	if crawler.headless {
-		node, err := crawler.getHtmlContentHeadless(url)
+		fetched, err := crawler.getHtmlContentHeadless(url)
 		if err != nil {
 			crawler.propagateWarning(err.Error())
 			return
 		}
 
-		htmlNode = node
+		pg = fetched
 	} else {
-		node, err := crawler.getHtmlContent(url)
+		fetched, err := crawler.getHtmlContent(url)
 		if err != nil {
 			crawler.propagateWarning(err.Error())
 			return
 		}
 
-		htmlNode = node
+		pg = fetched
 	}
 
+	htmlNode := pg.node
+
 	// time request was made
 	reqTime := time.Now()
 
+	if crawler.soft404 && crawler.checkSoft404(pg) {
+		crawler.waitDelay(reqTime)
+		<-semaphore
+		return
+	}
+
+	if crawler.dirListings && crawler.handleDirectoryListing(pg) {
+		crawler.waitDelay(reqTime)
+		<-semaphore
+		return
+	}
+
 	// TODO: make this asynchronous
 	crawler.findLinks(htmlNode, url)
 
+	if crawler.contacts {
+		crawler.findContacts(htmlNode, url)
+	}
+
+	if crawler.comments != nil {
+		crawler.findComments(htmlNode, url)
+	}
+
+	if crawler.fingerprinter != nil {
+		crawler.findTechnologies(pg)
+	}
+
+	if crawler.favicons {
+		crawler.findIcons(pg)
+	}
+
+	if crawler.headerAudit {
+		crawler.auditHeaders(pg)
+	}
+
+	if crawler.sourceMaps {
+		crawler.findScripts(pg)
+	}
+
+	if crawler.realtime {
+		crawler.findRealtimeEndpoints(pg)
+	}
+
+	if crawler.realtime || crawler.comments != nil {
+		crawler.queueInScopeScripts(pg)
+	}
+
+	if len(crawler.hostProbes) > 0 {
+		crawler.mutex.Lock()
+		crawler.queueHost(pg.url)
+		crawler.mutex.Unlock()
+	}
+
+	if len(crawler.dirProbes) > 0 {
+		crawler.mutex.Lock()
+		crawler.queueDirs(pg.url)
+		crawler.mutex.Unlock()
+	}
+
 	// verifies how long to timeout before making next request
+	crawler.waitDelay(reqTime)
+
+	<-semaphore
+}
+
+// Sleeps for the remainder of the crawler's delay since the request was made
+func (crawler *Crawler) waitDelay(reqTime time.Time) {
 	elapsed := time.Since(reqTime)
 	if int(elapsed.Milliseconds()) < crawler.delay {
 		dur := crawler.delay - int(elapsed.Milliseconds())
 		time.Sleep(time.Duration(dur) * time.Millisecond)
 	}
+}
 
-	<-semaphore
+// Queues the URL's host to be probed if it is in scope (shares the seed's registrable domain) and was not
+// queued before. Must be called with the crawler's mutex held.
+func (crawler *Crawler) queueHost(u url.URL) {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return
+	}
+
+	if !shared.SameBaseDomain(u.Hostname(), crawler.seedUrl.Hostname()) {
+		return
+	}
+
+	host := url.URL{Scheme: u.Scheme, Host: u.Host}
+	if _, exists := crawler.hostMap[host.String()]; exists {
+		return
+	}
+
+	crawler.hostMap[host.String()] = struct{}{}
+	crawler.hostQueue = append(crawler.hostQueue, host)
 }
 
-// Gets HTML content from page with simple HTTP client
-func (crawler *Crawler) getHtmlContent(url url.URL) (*html.Node, error) {
-	req, err := generateRequest(url)
+// Runs the host probes for every queued host concurrently, respecting the crawler's thread count
+func (crawler *Crawler) probeQueuedHosts() {
+	crawler.mutex.Lock()
+	queue := crawler.hostQueue
+	crawler.hostQueue = []url.URL{}
+	crawler.mutex.Unlock()
+
+	semaphore := make(chan struct{}, crawler.threads)
+	var wg sync.WaitGroup
+
+	for _, host := range queue {
+		wg.Add(1)
+
+		go func(host url.URL) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			for _, probe := range crawler.hostProbes {
+				probe(host)
+			}
+		}(host)
+	}
+
+	wg.Wait()
+}
+
+// Queues every directory of the URL's path (e.g. /, /a/ and /a/b/ for /a/b/c.php) to be probed if the URL is
+// in scope and the directory was not queued before. Must be called with the crawler's mutex held.
+func (crawler *Crawler) queueDirs(u url.URL) {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return
+	}
+
+	if !shared.SameBaseDomain(u.Hostname(), crawler.seedUrl.Hostname()) {
+		return
+	}
+
+	for _, dirPath := range ParentDirs(u.Path) {
+		dir := url.URL{Scheme: u.Scheme, Host: u.Host, Path: dirPath}
+		if _, exists := crawler.dirMap[dir.String()]; exists {
+			continue
+		}
+
+		crawler.dirMap[dir.String()] = struct{}{}
+		crawler.dirQueue = append(crawler.dirQueue, dir)
+	}
+}
+
+// Runs the directory probes for every queued directory concurrently, respecting the crawler's thread count
+func (crawler *Crawler) probeQueuedDirs() {
+	crawler.mutex.Lock()
+	queue := crawler.dirQueue
+	crawler.dirQueue = []url.URL{}
+	crawler.mutex.Unlock()
+
+	semaphore := make(chan struct{}, crawler.threads)
+	var wg sync.WaitGroup
+
+	for _, dir := range queue {
+		wg.Add(1)
+
+		go func(dir url.URL) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			for _, probe := range crawler.dirProbes {
+				probe(dir)
+			}
+		}(dir)
+	}
+
+	wg.Wait()
+}
+
+// Sends a request for a probe, and sleeps afterwards to respect the crawler's delay.
+// The response is returned regardless of its status code, with the body limited to maxBodySize.
+func (crawler *Crawler) probeRequest(method string, target url.URL, contentType string, body []byte) (int, http.Header, []byte, error) {
+	return crawler.probeRequestWith(http.DefaultClient, method, target, contentType, body)
+}
+
+// Sends a request for a probe with the given client (e.g. to stop at redirects)
+func (crawler *Crawler) probeRequestWith(client *http.Client, method string, target url.URL, contentType string, body []byte) (int, http.Header, []byte, error) {
+	reqTime := time.Now()
+	defer crawler.waitDelay(reqTime)
+
+	req, err := crawler.newRequest(method, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return 0, nil, nil, err
+	}
+
+	return resp.StatusCode, resp.Header, respBody, nil
+}
+
+// Sends a GET request with the crawler's headers and cookies
+func (crawler *Crawler) sendRequest(url url.URL) (*http.Response, error) {
+	req, err := crawler.newRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := http.DefaultClient
+	return client.Do(req)
+}
+
+// Creates a request with the crawler's headers and cookies
+func (crawler *Crawler) newRequest(method string, url url.URL, body io.Reader) (*http.Request, error) {
+	req, err := generateRequest(method, url, body)
 	if err != nil {
 		return nil, err
 	}
@@ -146,53 +571,112 @@ func (crawler *Crawler) getHtmlContent(url url.URL) (*html.Node, error) {
 		}
 	}
 
-	client := http.DefaultClient
-	resp, err := client.Do(req)
+	return req, nil
+}
+
+// Gets HTML content from page with simple HTTP client
+func (crawler *Crawler) getHtmlContent(url url.URL) (page, error) {
+	resp, err := crawler.sendRequest(url)
 	if err != nil {
-		return nil, err
+		return page{}, err
 	}
 
 	defer resp.Body.Close()
 
-	htmlDoc, err := html.Parse(resp.Body)
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return page{}, err
+	}
+
+	htmlDoc, err := html.Parse(bytes.NewReader(body))
 	if err != nil {
 		crawler.propagateWarning(err.Error())
-		return nil, err
+		return page{}, err
 	}
 
-	return htmlDoc, nil
+	return page{
+		url:        url,
+		statusCode: resp.StatusCode,
+		header:     resp.Header,
+		body:       body,
+		node:       htmlDoc,
+	}, nil
 }
 
 // Gets HTML content from page with headless Chrome browser
-func (crawler *Crawler) getHtmlContentHeadless(url url.URL) (*html.Node, error) {
+func (crawler *Crawler) getHtmlContentHeadless(url url.URL) (page, error) {
 	ctx, cancel := chromedp.NewContext(context.Background())
 	defer cancel()
 
-	network.Enable()
-
 	// the ActionFunc is nil if the cookie hashmap is empty
 	setCookiesFunc, err := crawler.setHeadlessCookie(ctx, url)
 	if err != nil {
-		return nil, err
+		return page{}, err
 	}
 
+	// keeps the response of the main document (the last one in case of redirects)
+	var respMutex sync.Mutex
+	statusCode := 0
+	header := http.Header{}
+	var websockets, eventSources []string
+	chromedp.ListenTarget(ctx, func(ev interface{}) {
+		respMutex.Lock()
+		defer respMutex.Unlock()
+
+		switch event := ev.(type) {
+		case *network.EventWebSocketCreated:
+			websockets = append(websockets, event.URL)
+		case *network.EventRequestWillBeSent:
+			if event.Type == network.ResourceTypeEventSource {
+				eventSources = append(eventSources, event.Request.URL)
+			}
+		case *network.EventResponseReceived:
+			if event.Type != network.ResourceTypeDocument {
+				return
+			}
+
+			statusCode = int(event.Response.Status)
+			header = http.Header{}
+			for key, value := range event.Response.Headers {
+				// Chrome joins repeated headers with new lines
+				for _, v := range strings.Split(fmt.Sprint(value), "\n") {
+					header.Add(key, v)
+				}
+			}
+		}
+	})
+
+	// the whole document is needed since <head> holds meta tags used for fingerprinting
 	var content string
 	if err := chromedp.Run(ctx,
+		network.Enable(),
 		setCookiesFunc,
 		crawler.setHeadlessHeader(),
 		chromedp.Navigate(url.String()),
 		chromedp.WaitVisible("html", chromedp.ByQuery),
-		chromedp.OuterHTML("body", &content),
+		chromedp.OuterHTML("html", &content),
 	); err != nil {
-		return nil, err
+		return page{}, err
 	}
 
 	c, err := html.Parse(strings.NewReader(content))
 	if err != nil {
-		return nil, err
+		return page{}, err
 	}
 
-	return c, nil
+	respMutex.Lock()
+	defer respMutex.Unlock()
+
+	return page{
+		url:        url,
+		statusCode: statusCode,
+		header:     header,
+		body:       []byte(content),
+		node:       c,
+
+		websockets:   websockets,
+		eventSources: eventSources,
+	}, nil
 }
 
 // Returns chromedp ActionFunc that sets the cookies per each chrome request
@@ -268,6 +752,18 @@ func (crawler *Crawler) handleFoundUrl(urlStr, host, scheme string) {
 		crawler.toCrawl = append(crawler.toCrawl, url)
 		crawler.urlMap[url.String()] = url
 
+		if crawler.favicons {
+			crawler.queueIcon(url, "/favicon.ico")
+		}
+
+		if len(crawler.hostProbes) > 0 {
+			crawler.queueHost(url)
+		}
+
+		if len(crawler.dirProbes) > 0 {
+			crawler.queueDirs(url)
+		}
+
 		scanned := shared.ScannedItem{
 			Url:    url,
 			Source: CRAWLER_NAME,
@@ -277,6 +773,134 @@ func (crawler *Crawler) handleFoundUrl(urlStr, host, scheme string) {
 	}
 }
 
+// Extracts contact information from page and propagates the ones that were not found before
+func (crawler *Crawler) findContacts(node *html.Node, currUrl url.URL) {
+	for _, contact := range ExtractContacts(node, crawler.seedUrl.Hostname()) {
+		crawler.handleFinding(shared.Finding{
+			Type:   contact.Type,
+			Value:  contact.Value,
+			Url:    currUrl,
+			Source: shared.Contacts,
+		})
+	}
+}
+
+// Feeds URLs mentioned in the page's comments to the frontier and propagates the comments that pass the filter
+func (crawler *Crawler) findComments(node *html.Node, currUrl url.URL) {
+	crawler.handleComments(ExtractComments(node), currUrl)
+}
+
+// Feeds URLs mentioned in comments to the frontier and propagates the comments that pass the filter
+func (crawler *Crawler) handleComments(comments []string, currUrl url.URL) {
+	for _, comment := range comments {
+		for _, urlStr := range ExtractCommentUrls(comment) {
+			crawler.handleFoundUrl(urlStr, currUrl.Host, currUrl.Scheme)
+		}
+
+		if !crawler.comments.IsInteresting(comment) {
+			continue
+		}
+
+		crawler.handleFinding(shared.Finding{
+			Type:   shared.Comment,
+			Value:  comment,
+			Url:    currUrl,
+			Source: shared.Comments,
+		})
+	}
+}
+
+// Detects the technologies behind the page's host and propagates the ones not yet reported for that host
+func (crawler *Crawler) findTechnologies(pg page) {
+	input := NewFingerprintInput(pg.header, pg.body, pg.node)
+
+	// favicons are only matched when the crawler happens to fetch one
+	if isFavicon(pg) {
+		input.FaviconHash = FaviconHash(pg.body)
+	}
+
+	for _, tech := range crawler.fingerprinter.Analyze(input) {
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.Technology,
+			Value:  tech.String(),
+			Url:    pg.url,
+			Source: shared.Fingerprint,
+		})
+	}
+}
+
+// Queues the scripts loaded by the page, as well as the page itself if it is a script
+func (crawler *Crawler) findScripts(pg page) {
+	crawler.mutex.Lock()
+	defer crawler.mutex.Unlock()
+
+	if strings.HasSuffix(pg.url.Path, ".js") {
+		crawler.queueScripts(pg, []string{pg.url.String()})
+	}
+
+	if pg.node != nil {
+		crawler.queueScripts(pg, ExtractScriptSources(pg.node))
+	}
+}
+
+// Audits the page's response once per host and path pattern, and propagates the issues found once per host
+func (crawler *Crawler) auditHeaders(pg page) {
+	// headless responses have no headers if Chrome did not report the main document
+	if len(pg.header) == 0 {
+		return
+	}
+
+	audit := AuditHeaders(pg.header, pg.url.Scheme == "https")
+	audit.Host = pg.url.Host
+	audit.Pattern = PathPattern(pg.url.Path)
+
+	key := audit.Host + audit.Pattern
+
+	crawler.mutex.Lock()
+	_, exists := crawler.headerAudits[key]
+	if !exists {
+		crawler.headerAudits[key] = audit
+	}
+	crawler.mutex.Unlock()
+
+	if exists {
+		return
+	}
+
+	// pages of the same host usually share their headers and cookies, so issues are reported once per host
+	for _, issue := range audit.Issues {
+		crawler.handleHostFinding(shared.Finding{
+			Type:   issue.Type,
+			Value:  issue.Value,
+			Url:    pg.url,
+			Source: shared.HeaderAudit,
+		})
+	}
+}
+
+// Deduplicates findings by type and value and sends new ones via comms.FindingChan
+func (crawler *Crawler) handleFinding(finding shared.Finding) {
+	crawler.handleUniqueFinding(finding, string(finding.Type)+"|"+finding.Value)
+}
+
+// Deduplicates findings by host, type and value, so the same finding can be reported once per host
+func (crawler *Crawler) handleHostFinding(finding shared.Finding) {
+	crawler.handleUniqueFinding(finding, finding.Url.Host+"|"+string(finding.Type)+"|"+finding.Value)
+}
+
+// Propagates the finding if no other finding with the same key was propagated before
+func (crawler *Crawler) handleUniqueFinding(finding shared.Finding, key string) {
+	crawler.mutex.Lock()
+	defer crawler.mutex.Unlock()
+
+	if _, exists := crawler.findingMap[key]; exists {
+		return
+	}
+
+	crawler.findingMap[key] = struct{}{}
+	crawler.propagateFinding(finding)
+}
+
 func (crawler *Crawler) propagateWarning(str string) {
 	crawler.comms.WarningChan <- str
 }
@@ -284,3 +908,7 @@ func (crawler *Crawler) propagateWarning(str string) {
 func (crawler *Crawler) propagateData(scanned shared.ScannedItem) {
 	crawler.comms.DataChan <- scanned
 }
+
+func (crawler *Crawler) propagateFinding(finding shared.Finding) {
+	crawler.comms.FindingChan <- finding
+}
@@ -172,3 +172,20 @@ func TestCrawlerLockedHostHeadless(t *testing.T) {
 		}
 	}
 }
+
+// Runs the crawl, returning the findings it propagated
+func collectCrawlFindings(crawler *Crawler, comms shared.CommsChannels) []shared.Finding {
+	go crawler.Crawl(0)
+
+	var findings []shared.Finding
+	for {
+		select {
+		case finding := <-comms.FindingChan:
+			findings = append(findings, finding)
+		case <-comms.DataChan:
+		case <-comms.WarningChan:
+		case <-comms.CrawlDoneChan:
+			return findings
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package osint
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+// Servers whose directory listings are recognized
+const (
+	ApacheListing  = "Apache"
+	NginxListing   = "nginx"
+	IISListing     = "IIS"
+	GenericListing = "generic"
+)
+
+var (
+	// Apache and nginx (2023-01-31 12:00, 31-Jan-2023 12:00) and IIS (1/31/2023 12:00 PM, Tuesday, January 31, 2023 12:00 PM)
+	listingDateRegex = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}(?::\d{2})?|\d{2}-[A-Za-z]{3}-\d{4} \d{2}:\d{2}|(?:[A-Za-z]+, [A-Za-z]+ \d{1,2}, \d{4}|\d{1,2}/\d{1,2}/\d{4})\s+\d{1,2}:\d{2}(?:\s*[AP]M)?`)
+	listingSizeRegex = regexp.MustCompile(`(?i)^(?:\d+(?:\.\d+)?[KMGT]?|<dir>|-)$`)
+	iisTitleRegex    = regexp.MustCompile(`(?i)<title>[^<]* - /[^<]*</title>`)
+)
+
+// File or subdirectory listed by a directory listing. Size and Modified are kept as displayed by the server.
+type ListingEntry struct {
+	Url      url.URL
+	Size     string
+	Modified string
+	IsDir    bool
+}
+
+// Returns the server that generated the page if it is a directory listing, or an empty string otherwise
+func DetectDirectoryListing(body []byte) string {
+	lower := bytes.ToLower(body)
+
+	switch {
+	case bytes.Contains(lower, []byte("[to parent directory]")) && iisTitleRegex.Match(body):
+		return IISListing
+	case !bytes.Contains(lower, []byte("<title>index of /")):
+		return ""
+	case bytes.Contains(lower, []byte("?c=n;o=d")) || bytes.Contains(lower, []byte("<address>apache")):
+		return ApacheListing
+	case bytes.Contains(lower, []byte("<hr><pre>")):
+		return NginxListing
+	}
+
+	return GenericListing
+}
+
+// Parses the entries of a directory listing. Only links to paths under the listing's own path are kept, which
+// excludes parent directory and sorting links. Listings requested without a trailing slash (e.g. /files) are
+// resolved as the directory itself, since their entries are relative to it.
+func ParseDirectoryListing(node *html.Node, listingUrl url.URL) []ListingEntry {
+	if !strings.HasSuffix(listingUrl.Path, "/") {
+		listingUrl.Path += "/"
+		listingUrl.RawPath = ""
+	}
+
+	var entries []ListingEntry
+	seen := map[string]struct{}{}
+
+	add := func(href string, metadata string) {
+		ref, err := url.Parse(href)
+		if err != nil {
+			return
+		}
+
+		resolved := *listingUrl.ResolveReference(ref)
+		resolved.RawQuery = ""
+		resolved.Fragment = ""
+
+		if resolved.Host != listingUrl.Host || !strings.HasPrefix(resolved.Path, listingUrl.Path) ||
+			len(resolved.Path) <= len(listingUrl.Path) {
+			return
+		}
+
+		if _, exists := seen[resolved.Path]; exists {
+			return
+		}
+		seen[resolved.Path] = struct{}{}
+
+		entry := ListingEntry{Url: resolved, IsDir: strings.HasSuffix(resolved.Path, "/")}
+
+		modified := listingDateRegex.FindString(metadata)
+		entry.Modified = strings.Join(strings.Fields(modified), " ")
+
+		fields := strings.Fields(strings.Replace(metadata, modified, " ", 1))
+		if len(fields) > 0 && listingSizeRegex.MatchString(fields[len(fields)-1]) {
+			size := fields[len(fields)-1]
+			if strings.EqualFold(size, "<dir>") {
+				entry.IsDir = true
+			} else if size != "-" {
+				entry.Size = size
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode {
+			switch node.Data {
+			case "tr":
+				// Apache's fancy index keeps the metadata in the cells next to the link
+				if link := findFirst(node, "a"); link != nil {
+					add(getAttr(link, "href"), nodeText(node))
+				}
+				return
+			case "pre":
+				parsePreListing(node, add)
+				return
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(node)
+
+	return entries
+}
+
+// Parses listings where every entry is a line of a <pre> element. nginx and Apache display the metadata after
+// the link, while IIS displays it before.
+func parsePreListing(pre *html.Node, add func(href string, metadata string)) {
+	before := ""
+	var href *string
+	after := ""
+
+	flush := func() {
+		if href != nil {
+			add(*href, before+" "+after)
+		}
+
+		before, after, href = "", "", nil
+	}
+
+	for child := pre.FirstChild; child != nil; child = child.NextSibling {
+		switch {
+		case child.Type == html.ElementNode && child.Data == "a":
+			if href != nil {
+				flush()
+			}
+
+			link := getAttr(child, "href")
+			href = &link
+		case child.Type == html.ElementNode && child.Data == "br":
+			flush()
+		case child.Type == html.TextNode:
+			lines := strings.Split(child.Data, "\n")
+			for i, line := range lines {
+				if i > 0 {
+					flush()
+				}
+
+				if href == nil {
+					before += line
+				} else {
+					after += line
+				}
+			}
+		}
+	}
+
+	flush()
+}
+
+func findFirst(node *html.Node, tag string) *html.Node {
+	if node.Type == html.ElementNode && node.Data == tag {
+		return node
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if found := findFirst(child, tag); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func nodeText(node *html.Node) string {
+	if node.Type == html.TextNode {
+		return node.Data
+	}
+
+	var text []string
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		text = append(text, nodeText(child))
+	}
+
+	return strings.Join(text, " ")
+}
+
+func (entry ListingEntry) String() string {
+	var metadata []string
+	if entry.IsDir {
+		metadata = append(metadata, "directory")
+	}
+
+	if entry.Size != "" {
+		metadata = append(metadata, "size "+entry.Size)
+	}
+
+	if entry.Modified != "" {
+		metadata = append(metadata, "modified "+entry.Modified)
+	}
+
+	if len(metadata) == 0 {
+		return entry.Url.Path
+	}
+
+	return entry.Url.Path + " (" + strings.Join(metadata, ", ") + ")"
+}
+
+// Reports the page and its entries if it is a directory listing, and queues its subdirectories to be walked.
+// Returns false if the page is not a directory listing.
+func (crawler *Crawler) handleDirectoryListing(pg page) bool {
+	if pg.node == nil {
+		return false
+	}
+
+	server := DetectDirectoryListing(pg.body)
+	if server == "" {
+		return false
+	}
+
+	crawler.mutex.Lock()
+	crawler.listingMap[pg.url.String()] = struct{}{}
+	crawler.mutex.Unlock()
+
+	crawler.reportDirectoryListing(pg.url, server, ParseDirectoryListing(pg.node, pg.url))
+
+	return true
+}
+
+func (crawler *Crawler) reportDirectoryListing(listingUrl url.URL, server string, entries []ListingEntry) {
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.DirectoryListing,
+		Value:  listingUrl.String() + " (" + server + ")",
+		Url:    listingUrl,
+		Source: shared.DirListing,
+	})
+
+	for _, entry := range entries {
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.ListedFile,
+			Value:  entry.String(),
+			Url:    listingUrl,
+			Source: shared.DirListing,
+		})
+
+		if !entry.IsDir {
+			continue
+		}
+
+		crawler.mutex.Lock()
+		if _, exists := crawler.listingMap[entry.Url.String()]; !exists {
+			crawler.listingMap[entry.Url.String()] = struct{}{}
+			crawler.listingQueue = append(crawler.listingQueue, entry.Url)
+		}
+		crawler.mutex.Unlock()
+	}
+}
+
+// Walks the subdirectories of the listings found so far until there are none left or the budget is spent.
+// The walk is not bound by the crawl depth.
+func (crawler *Crawler) walkQueuedListings() {
+	for {
+		crawler.mutex.Lock()
+		queue := crawler.listingQueue
+		if len(queue) > crawler.listingBudget {
+			queue = queue[:crawler.listingBudget]
+		}
+		crawler.listingBudget -= len(queue)
+		crawler.listingQueue = []url.URL{}
+		crawler.mutex.Unlock()
+
+		if len(queue) == 0 {
+			return
+		}
+
+		crawler.runConcurrently(len(queue), func(i int) {
+			crawler.walkListing(queue[i])
+		})
+	}
+}
+
+func (crawler *Crawler) walkListing(dir url.URL) {
+	status, _, body, err := crawler.probeRequest(http.MethodGet, dir, "", nil)
+	if err != nil || status != http.StatusOK {
+		return
+	}
+
+	server := DetectDirectoryListing(body)
+	if server == "" {
+		return
+	}
+
+	node, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	crawler.reportDirectoryListing(dir, server, ParseDirectoryListing(node, dir))
+}
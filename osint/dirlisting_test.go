@@ -0,0 +1,118 @@
+package osint
+
+import (
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+const apacheListing = `<!DOCTYPE HTML PUBLIC "-//W3C//DTD HTML 3.2 Final//EN">
+<html><head><title>Index of /files</title></head><body>
+<h1>Index of /files</h1>
+<table>
+<tr><th valign="top"><img src="/icons/blank.gif" alt="[ICO]"></th><th><a href="?C=N;O=D">Name</a></th><th><a href="?C=M;O=A">Last modified</a></th><th><a href="?C=S;O=A">Size</a></th></tr>
+<tr><td valign="top"><img src="/icons/back.gif" alt="[PARENTDIR]"></td><td><a href="/">Parent Directory</a></td><td>&nbsp;</td><td align="right">  - </td></tr>
+<tr><td valign="top"><img src="/icons/folder.gif" alt="[DIR]"></td><td><a href="backups/">backups/</a></td><td align="right">2023-03-14 09:12  </td><td align="right">  - </td></tr>
+<tr><td valign="top"><img src="/icons/text.gif" alt="[TXT]"></td><td><a href="notes.txt">notes.txt</a></td><td align="right">2023-03-15 17:40  </td><td align="right">1.2K</td></tr>
+</table>
+<address>Apache/2.4.57 (Debian) Server at example.com Port 80</address>
+</body></html>`
+
+const nginxListing = `<html>
+<head><title>Index of /files/</title></head>
+<body>
+<h1>Index of /files/</h1><hr><pre><a href="../">../</a>
+<a href="backups/">backups/</a>                                           14-Mar-2023 09:12                   -
+<a href="notes.txt">notes.txt</a>                                          15-Mar-2023 17:40                1234
+</pre><hr></body>
+</html>`
+
+const iisListing = `<html><head><title>example.com - /files/</title></head><body><H1>example.com - /files/</H1><hr>
+
+<pre><A HREF="/">[To Parent Directory]</A><br><br>     3/14/2023  9:12 AM        &lt;dir&gt; <A HREF="/files/backups/">backups</A><br>     3/15/2023  5:40 PM         1234 <A HREF="/files/notes.txt">notes.txt</A><br></pre><hr></body></html>`
+
+func TestDetectDirectoryListing(t *testing.T) {
+	cases := map[string]string{
+		apacheListing: ApacheListing,
+		nginxListing:  NginxListing,
+		iisListing:    IISListing,
+		"<html><title>Index of /</title><ul><li><a href=\"a\">a</a></li></ul></html>": GenericListing,
+		"<html><title>Home</title><body>Index of products</body></html>":              "",
+	}
+
+	for body, expected := range cases {
+		if res := DetectDirectoryListing([]byte(body)); res != expected {
+			t.Errorf("DetectDirectoryListing expected %q; got %q", expected, res)
+		}
+	}
+}
+
+func TestParseDirectoryListing(t *testing.T) {
+	listingUrl, _ := url.Parse("https://example.com/files/")
+	backups, _ := url.Parse("https://example.com/files/backups/")
+	notes, _ := url.Parse("https://example.com/files/notes.txt")
+
+	cases := []struct {
+		name     string
+		body     string
+		expected []ListingEntry
+	}{
+		{
+			name: "apache",
+			body: apacheListing,
+			expected: []ListingEntry{
+				{Url: *backups, Modified: "2023-03-14 09:12", IsDir: true},
+				{Url: *notes, Size: "1.2K", Modified: "2023-03-15 17:40"},
+			},
+		},
+		{
+			name: "nginx",
+			body: nginxListing,
+			expected: []ListingEntry{
+				{Url: *backups, Modified: "14-Mar-2023 09:12", IsDir: true},
+				{Url: *notes, Size: "1234", Modified: "15-Mar-2023 17:40"},
+			},
+		},
+		{
+			name: "iis",
+			body: iisListing,
+			expected: []ListingEntry{
+				{Url: *backups, Modified: "3/14/2023 9:12 AM", IsDir: true},
+				{Url: *notes, Size: "1234", Modified: "3/15/2023 5:40 PM"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		node, err := html.Parse(strings.NewReader(c.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res := ParseDirectoryListing(node, *listingUrl)
+		if !reflect.DeepEqual(res, c.expected) {
+			t.Errorf("ParseDirectoryListing (%s) expected %v; got %v", c.name, c.expected, res)
+		}
+	}
+
+	// the entries of a listing requested without a trailing slash are relative to the directory
+	node, _ := html.Parse(strings.NewReader(apacheListing))
+	withoutSlash, _ := url.Parse("https://example.com/files")
+	if res := ParseDirectoryListing(node, *withoutSlash); !reflect.DeepEqual(res, cases[0].expected) {
+		t.Errorf("ParseDirectoryListing expected %v for listing without trailing slash; got %v", cases[0].expected, res)
+	}
+}
+
+func TestListingEntryString(t *testing.T) {
+	notes, _ := url.Parse("https://example.com/files/notes.txt")
+
+	entry := ListingEntry{Url: *notes, Size: "1234", Modified: "15-Mar-2023 17:40"}
+	expected := "/files/notes.txt (size 1234, modified 15-Mar-2023 17:40)"
+
+	if res := entry.String(); res != expected {
+		t.Errorf("String expected %q; got %q", expected, res)
+	}
+}
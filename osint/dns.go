@@ -2,11 +2,12 @@ package osint
 
 import (
 	"fmt"
+	"io"
 	"net"
 	"net/url"
-	"os"
 	"strings"
-	"text/tabwriter"
+	"sync"
+	"time"
 
 	"github.com/miekg/dns"
 
@@ -20,105 +21,209 @@ const (
 	noIPsErr              = "no IPs found for domain"
 )
 
-// Attempts to perform a DNS zone transfer for each name server of a given domain
-func ZoneTransfer(domain string) ([]url.URL, []error) {
-	nameServers, err := getDNSServers(domain)
+// Resource record received in a zone transfer or a query, along with the name server that answered it
+type ZoneRecord struct {
+	Name       string
+	Type       string
+	TTL        uint32
+	Rdata      string
+	NameServer string
+}
+
+// Address of a name server of a domain. IP is nil if the name server's host could not be resolved.
+type NameServer struct {
+	Host string
+	IP   net.IP
+}
+
+// Result of a zone transfer attempt against a single name server address
+type AxfrOutcome struct {
+	NameServer NameServer
+	Records    int
+	Err        error
+}
+
+// Port zone transfers are requested on
+var axfrPort = "53"
+
+// Attempts to perform a DNS zone transfer over TCP against every IPv4 and IPv6 address of every name server of a
+// given domain, concurrently and with a timeout per server. The name servers are looked up through the resolver. The
+// outcome of every attempt is returned, and an error is only returned if the name servers could not be found at all.
+func (resolver *SubdomainResolver) ZoneTransfer(domain string, timeout time.Duration) ([]ZoneRecord, []AxfrOutcome, error) {
+	nameServers, err := resolver.getDNSServers(domain)
 	if err != nil {
-		return []url.URL{}, []error{err}
+		return []ZoneRecord{}, []AxfrOutcome{}, err
 	}
 
-	var errs []error
+	outcomes := make([]AxfrOutcome, len(nameServers))
+	transferred := make([][]ZoneRecord, len(nameServers))
 
-	var foundDomains []url.URL
-	for _, ns := range nameServers {
-		subdomains, errs := performAxfr(domain, ns.String())
-		if len(errs) > 0 {
-			errs = append(errs, errs...)
+	var wg sync.WaitGroup
+	for i, ns := range nameServers {
+		outcomes[i].NameServer = ns
+
+		if ns.IP == nil {
+			outcomes[i].Err = fmt.Errorf(noIPsErr)
+			continue
 		}
-		foundDomains = append(foundDomains, subdomains...)
+
+		wg.Add(1)
+
+		go func(i int, ns NameServer) {
+			defer wg.Done()
+
+			transferred[i], outcomes[i].Err = performAxfr(domain, ns, timeout)
+			outcomes[i].Records = len(transferred[i])
+		}(i, ns)
+	}
+
+	wg.Wait()
+
+	var records []ZoneRecord
+	for _, serverRecords := range transferred {
+		records = append(records, serverRecords...)
 	}
 
-	return foundDomains, errs
+	return records, outcomes, nil
 }
 
-func getDNSServers(domain string) ([]net.IP, error) {
-	nsRecords, err := net.LookupNS(domain)
+// Returns every address of every name server of the domain. Name servers that cannot be resolved are returned
+// without an IP, so they are reported instead of aborting the transfer.
+func (resolver *SubdomainResolver) getDNSServers(domain string) ([]NameServer, error) {
+	res, err := resolver.query(domain, dns.TypeNS)
 	if err != nil {
-		return []net.IP{}, err
+		return []NameServer{}, err
 	}
 
-	var nameServers []net.IP
-	for _, nsRecord := range nsRecords {
-		ipv4, err := getIPV4(strings.TrimSuffix(nsRecord.Host, "."))
-		if err != nil {
-			return []net.IP{}, err
+	var nameServers []NameServer
+	for _, rr := range res.Answer {
+		nsRecord, ok := rr.(*dns.NS)
+		if !ok {
+			continue
+		}
+		host := strings.ToLower(strings.TrimSuffix(nsRecord.Ns, "."))
+
+		addresses := resolver.addresses(host)
+		if len(addresses) == 0 {
+			nameServers = append(nameServers, NameServer{Host: host})
+			continue
 		}
 
-		nameServers = append(nameServers, ipv4)
+		for _, address := range addresses {
+			nameServers = append(nameServers, NameServer{Host: host, IP: net.ParseIP(address)})
+		}
 	}
 
 	if len(nameServers) == 0 {
-		return []net.IP{}, fmt.Errorf(noDNSServersFoundErr)
+		return []NameServer{}, fmt.Errorf(noDNSServersFoundErr)
 	}
 
 	return nameServers, nil
 }
 
-func getIPV4(domain string) (net.IP, error) {
-	ips, err := net.LookupIP(domain)
-	if err != nil {
-		return net.IP{}, err
+func (ns NameServer) String() string {
+	if ns.IP == nil {
+		return ns.Host
 	}
 
-	if len(ips) == 0 {
-		return net.IP{}, fmt.Errorf(noIPsErr)
+	return ns.Host + " (" + ns.IP.String() + ")"
+}
+
+// Sends zone transfer request for spefific name server address over TCP, and returns every record it transfers
+func performAxfr(domain string, ns NameServer, timeout time.Duration) ([]ZoneRecord, error) {
+	transfer := &dns.Transfer{DialTimeout: timeout, ReadTimeout: timeout, WriteTimeout: timeout}
+	msg := new(dns.Msg)
+	msg.SetAxfr(dns.Fqdn(domain))
+
+	ch, err := transfer.In(msg, net.JoinHostPort(ns.IP.String(), axfrPort))
+	if err != nil {
+		return []ZoneRecord{}, err
 	}
 
-	for _, ip := range ips {
-		if ip.To4() != nil {
-			return ip, nil
+	var records []ZoneRecord
+	for env := range ch {
+		if env.Error != nil {
+			return []ZoneRecord{}, env.Error
+		}
+
+		for _, rr := range env.RR {
+			records = append(records, newZoneRecord(rr, ns.String()))
 		}
 	}
 
-	return net.IP{}, fmt.Errorf(noIPsErr)
+	return records, nil
 }
 
-// Sends zone transfer request for spefific name server, and updates foundDomains list in-place
-func performAxfr(domain string, nameServerIP string) ([]url.URL, []error) {
-	transfer := new(dns.Transfer)
-	msg := new(dns.Msg)
-	msg.SetAxfr(domain + ".")
+func newZoneRecord(rr dns.RR, nameServer string) ZoneRecord {
+	header := rr.Header()
 
-	var errs []error
-
-	ch, err := transfer.In(msg, nameServerIP+":53")
-	if err != nil {
-		return []url.URL{}, []error{err}
+	return ZoneRecord{
+		Name:       header.Name,
+		Type:       dns.TypeToString[header.Rrtype],
+		TTL:        header.Ttl,
+		Rdata:      strings.TrimPrefix(rr.String(), header.String()),
+		NameServer: nameServer,
 	}
+}
 
-	writer := tabwriter.NewWriter(os.Stdout, 0, 1, 2, ' ', 0)
+// Formats the record as a line of an RFC 1035 master file
+func (record ZoneRecord) String() string {
+	return fmt.Sprintf("%s\t%d\tIN\t%s\t%s", record.Name, record.TTL, record.Type, record.Rdata)
+}
 
-	var foundDomains []url.URL
-	for env := range ch {
-		if env.Error != nil {
-			return []url.URL{}, []error{env.Error}
+// Returns the unique host names of the records
+func ZoneHosts(records []ZoneRecord) []url.URL {
+	var hosts []url.URL
+	for _, record := range records {
+		trimmed := strings.TrimSuffix(record.Name, ".")
+
+		host, err := url.Parse(trimmed)
+		if err != nil {
+			continue
 		}
 
-		for _, rr := range env.RR {
-			trimmed := strings.TrimSuffix(rr.Header().Name, ".")
+		if !shared.SliceContainsURL(hosts, *host) {
+			hosts = append(hosts, *host)
+		}
+	}
 
-			url, err := url.Parse(trimmed)
-			if err != nil {
-				errs = append(errs, err)
-			}
+	return hosts
+}
 
-			if !shared.SliceContainsURL(foundDomains, *url) {
-				foundDomains = append(foundDomains, *url)
-			}
+// Returns the name servers that transferred the records, in the order they were attempted
+func ZoneNameServers(records []ZoneRecord) []string {
+	var nameServers []string
+	for _, record := range records {
+		if !shared.SliceContains(nameServers, record.NameServer) {
+			nameServers = append(nameServers, record.NameServer)
 		}
 	}
 
-	writer.Flush()
+	return nameServers
+}
+
+// Writes the records as an RFC 1035 master file. Records transferred by more than one name server, and the SOA
+// record that closes every transfer, are written once.
+func WriteZoneFile(w io.Writer, domain string, records []ZoneRecord) error {
+	origin := dns.Fqdn(domain)
+
+	header := fmt.Sprintf("; zone transfer of %s from %s\n$ORIGIN %s\n", origin, strings.Join(ZoneNameServers(records), ", "), origin)
+	if _, err := io.WriteString(w, header); err != nil {
+		return err
+	}
+
+	written := map[string]struct{}{}
+	for _, record := range records {
+		line := record.String()
+		if _, exists := written[line]; exists {
+			continue
+		}
+		written[line] = struct{}{}
+
+		if _, err := io.WriteString(w, line+"\n"); err != nil {
+			return err
+		}
+	}
 
-	return foundDomains, errs
+	return nil
 }
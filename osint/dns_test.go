@@ -0,0 +1,190 @@
+package osint
+
+import (
+	"bytes"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func zoneRecords(t *testing.T, nameServer string, lines ...string) []ZoneRecord {
+	var records []ZoneRecord
+	for _, line := range lines {
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records = append(records, newZoneRecord(rr, nameServer))
+	}
+
+	return records
+}
+
+func TestNewZoneRecord(t *testing.T) {
+	res := zoneRecords(t, "192.0.2.1", "mail.example.com. 300 IN MX 10 mx1.example.com.")[0]
+
+	expected := ZoneRecord{
+		Name:       "mail.example.com.",
+		Type:       "MX",
+		TTL:        300,
+		Rdata:      "10 mx1.example.com.",
+		NameServer: "192.0.2.1",
+	}
+
+	if res != expected {
+		t.Errorf("newZoneRecord expected %+v; got %+v", expected, res)
+	}
+}
+
+func TestWriteZoneFile(t *testing.T) {
+	soa := "example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 900 1209600 300"
+	records := zoneRecords(t, "192.0.2.1", soa, "www.example.com. 300 IN A 192.0.2.10", soa)
+	records = append(records, zoneRecords(t, "2001:db8::1", soa, `example.com. 300 IN TXT "v=spf1 -all"`, soa)...)
+
+	var buf bytes.Buffer
+	if err := WriteZoneFile(&buf, "example.com", records); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "; zone transfer of example.com. from 192.0.2.1, 2001:db8::1\n" +
+		"$ORIGIN example.com.\n" +
+		"example.com.\t3600\tIN\tSOA\tns1.example.com. admin.example.com. 1 7200 900 1209600 300\n" +
+		"www.example.com.\t300\tIN\tA\t192.0.2.10\n" +
+		"example.com.\t300\tIN\tTXT\t\"v=spf1 -all\"\n"
+
+	if buf.String() != expected {
+		t.Errorf("WriteZoneFile expected %q; got %q", expected, buf.String())
+	}
+
+	// the exported file must be parseable as a zone again
+	parser := dns.NewZoneParser(&buf, "", "")
+	count := 0
+	for _, ok := parser.Next(); ok; _, ok = parser.Next() {
+		count++
+	}
+
+	if err := parser.Err(); err != nil || count != 3 {
+		t.Errorf("WriteZoneFile expected a zone file with 3 records; got %d, %v", count, err)
+	}
+}
+
+func TestZoneHosts(t *testing.T) {
+	records := zoneRecords(t, "192.0.2.1",
+		"example.com. 300 IN A 192.0.2.10",
+		"www.example.com. 300 IN A 192.0.2.10",
+		"www.example.com. 300 IN AAAA 2001:db8::10",
+	)
+
+	expected := []url.URL{{Path: "example.com"}, {Path: "www.example.com"}}
+
+	if res := ZoneHosts(records); !reflect.DeepEqual(res, expected) {
+		t.Errorf("ZoneHosts expected %v; got %v", expected, res)
+	}
+}
+
+// Starts a TCP DNS server on a local port that transfers the records for zone, and refuses any other zone
+func startAxfrServer(t *testing.T, zone string, records []dns.RR) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		if req.Question[0].Name != zone {
+			res := new(dns.Msg)
+			res.SetRcode(req, dns.RcodeRefused)
+			w.WriteMsg(res)
+			return
+		}
+
+		ch := make(chan *dns.Envelope)
+		transfer := new(dns.Transfer)
+		go transfer.Out(w, req, ch)
+
+		ch <- &dns.Envelope{RR: records}
+		close(ch)
+		w.Hijack()
+	})
+
+	server := &dns.Server{Listener: listener, Handler: handler}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	previous := axfrPort
+	axfrPort = port
+	t.Cleanup(func() { axfrPort = previous })
+}
+
+func TestPerformAxfr(t *testing.T) {
+	var records []dns.RR
+	for _, line := range []string{
+		"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 900 1209600 300",
+		"dev.example.com. 300 IN CNAME app.example.com.",
+		"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 900 1209600 300",
+	} {
+		rr, _ := dns.NewRR(line)
+		records = append(records, rr)
+	}
+
+	startAxfrServer(t, "example.com.", records)
+
+	ns := NameServer{Host: "ns1.example.com", IP: net.ParseIP("127.0.0.1")}
+
+	res, err := performAxfr("example.com", ns, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 3 || res[1].Type != "CNAME" || res[1].Rdata != "app.example.com." || res[1].NameServer != "ns1.example.com (127.0.0.1)" {
+		t.Errorf("performAxfr expected the transferred records; got %+v", res)
+	}
+
+	if _, err := performAxfr("other.com", ns, time.Second); err == nil {
+		t.Errorf("performAxfr expected error for refused transfer")
+	}
+}
+
+func TestZoneTransfer(t *testing.T) {
+	var records []dns.RR
+	for _, line := range []string{
+		"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 900 1209600 300",
+		"dev.example.com. 300 IN A 192.0.2.1",
+		"example.com. 3600 IN SOA ns1.example.com. admin.example.com. 1 7200 900 1209600 300",
+	} {
+		rr, _ := dns.NewRR(line)
+		records = append(records, rr)
+	}
+
+	startAxfrServer(t, "example.com.", records)
+
+	// ns2 cannot be resolved, so it is reported without being attempted
+	addr := startStubResolver(t,
+		"example.com. 300 IN NS ns1.example.com.",
+		"example.com. 300 IN NS ns2.example.com.",
+		"ns1.example.com. 300 IN A 127.0.0.1",
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, outcomes, err := resolver.ZoneTransfer("example.com", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 3 || res[1].Name != "dev.example.com." {
+		t.Errorf("ZoneTransfer expected the transferred records; got %+v", res)
+	}
+
+	if len(outcomes) != 2 || outcomes[0].Err != nil || outcomes[0].Records != 3 || outcomes[1].Err == nil || outcomes[1].NameServer.Host != "ns2.example.com" {
+		t.Errorf("ZoneTransfer expected a successful transfer from ns1 and a failure for ns2; got %+v", outcomes)
+	}
+}
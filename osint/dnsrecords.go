@@ -0,0 +1,117 @@
+package osint
+
+import (
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Record types queried for every host
+var EnumeratedTypes = []uint16{
+	dns.TypeA, dns.TypeAAAA, dns.TypeCNAME, dns.TypeMX, dns.TypeNS, dns.TypeTXT, dns.TypeSOA, dns.TypeCAA,
+}
+
+// Services whose SRV records are queried for every host, since SRV records only exist under service labels
+var srvServices = []string{
+	"_autodiscover._tcp", "_caldav._tcp", "_carddav._tcp", "_imaps._tcp", "_kerberos._tcp", "_ldap._tcp",
+	"_sip._tcp", "_sip._udp", "_sipfederationtls._tcp", "_submission._tcp", "_xmpp-client._tcp", "_xmpp-server._tcp",
+}
+
+// DNS records of a host, along with the host names its records point to (e.g. MX, CNAME and SRV targets)
+type HostRecords struct {
+	Host    string
+	Records []ZoneRecord
+	Targets []string
+}
+
+// Queries the A, AAAA, CNAME, MX, NS, TXT, SOA, CAA and SRV records of every host concurrently, returning the hosts
+// that have any records in the order they were given
+func (resolver *SubdomainResolver) EnumerateRecords(hosts []string) []HostRecords {
+	results := make([]HostRecords, len(hosts))
+
+	resolver.runWorkers(len(hosts), func(i int) {
+		results[i] = resolver.enumerateHost(hosts[i])
+	})
+
+	var enumerated []HostRecords
+	for _, result := range results {
+		if len(result.Records) > 0 {
+			enumerated = append(enumerated, result)
+		}
+	}
+
+	return enumerated
+}
+
+func (resolver *SubdomainResolver) enumerateHost(host string) HostRecords {
+	result := HostRecords{Host: strings.TrimSuffix(strings.ToLower(host), ".")}
+	seen := map[string]struct{}{}
+	owner := dns.Fqdn(result.Host)
+
+	add := func(res *dns.Msg, server string) {
+		for _, rr := range res.Answer {
+			// the records of a CNAME target belong to the target, which is enumerated on its own, except for the
+			// addresses the host resolves to
+			name := strings.ToLower(rr.Header().Name)
+			rrtype := rr.Header().Rrtype
+			if name != owner && !strings.HasSuffix(name, "."+owner) && rrtype != dns.TypeA && rrtype != dns.TypeAAAA {
+				continue
+			}
+
+			record := newZoneRecord(rr, server)
+			if _, exists := seen[record.String()]; exists {
+				continue
+			}
+			seen[record.String()] = struct{}{}
+
+			result.Records = append(result.Records, record)
+
+			if target := recordTarget(rr); target != "" && !shared.SliceContains(result.Targets, target) {
+				result.Targets = append(result.Targets, target)
+			}
+		}
+	}
+
+	for _, qtype := range EnumeratedTypes {
+		res, server, err := resolver.queryWithServer(result.Host, qtype)
+		if err != nil {
+			continue
+		}
+
+		// names that do not exist have no records of any type
+		if res.Rcode == dns.RcodeNameError {
+			return result
+		}
+
+		add(res, server)
+	}
+
+	for _, service := range srvServices {
+		res, server, err := resolver.queryWithServer(service+"."+result.Host, dns.TypeSRV)
+		if err == nil {
+			add(res, server)
+		}
+	}
+
+	return result
+}
+
+// Returns the host name a record points to, or an empty string if it does not point to one
+func recordTarget(rr dns.RR) string {
+	var target string
+
+	switch record := rr.(type) {
+	case *dns.CNAME:
+		target = record.Target
+	case *dns.MX:
+		target = record.Mx
+	case *dns.NS:
+		target = record.Ns
+	case *dns.SRV:
+		target = record.Target
+	}
+
+	return strings.ToLower(strings.TrimSuffix(target, "."))
+}
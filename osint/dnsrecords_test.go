@@ -0,0 +1,52 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEnumerateRecords(t *testing.T) {
+	addr := startStubResolver(t,
+		"example.com. 300 IN A 192.0.2.1",
+		"example.com. 300 IN MX 10 mx1.mail.example.net.",
+		"example.com. 300 IN NS ns1.example.com.",
+		`example.com. 300 IN TXT "v=spf1 include:_spf.example.net -all"`,
+		"example.com. 300 IN CAA 0 issue \"letsencrypt.org\"",
+		"_sip._tcp.example.com. 300 IN SRV 10 5 5060 sip.example.com.",
+		"www.example.com. 300 IN CNAME example.com.",
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 2, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := resolver.EnumerateRecords([]string{"example.com", "www.example.com", "missing.example.com"})
+	if len(res) != 2 {
+		t.Fatalf("EnumerateRecords expected 2 hosts with records; got %v", res)
+	}
+
+	var types []string
+	for _, record := range res[0].Records {
+		types = append(types, record.Type)
+		if record.NameServer != addr {
+			t.Errorf("EnumerateRecords expected records answered by %s; got %s", addr, record.NameServer)
+		}
+	}
+
+	expectedTypes := []string{"A", "MX", "NS", "TXT", "CAA", "SRV"}
+	if !reflect.DeepEqual(types, expectedTypes) {
+		t.Errorf("EnumerateRecords expected types %v; got %v", expectedTypes, types)
+	}
+
+	expectedTargets := []string{"mx1.mail.example.net", "ns1.example.com", "sip.example.com"}
+	if !reflect.DeepEqual(res[0].Targets, expectedTargets) {
+		t.Errorf("EnumerateRecords expected targets %v; got %v", expectedTargets, res[0].Targets)
+	}
+
+	// the CNAME is answered once, even though the A and CNAME queries both return it
+	if res[1].Host != "www.example.com" || len(res[1].Records) != 2 || !reflect.DeepEqual(res[1].Targets, []string{"example.com"}) {
+		t.Errorf("EnumerateRecords expected the CNAME and A records of www.example.com; got %+v", res[1])
+	}
+}
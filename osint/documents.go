@@ -0,0 +1,601 @@
+package osint
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	unsupportedDocumentErr = "document format is not supported"
+	documentTooLargeErr    = "document exceeds the maximum size"
+	invalidExifErr         = "EXIF data is not valid"
+)
+
+// Maximum size of a single decompressed PDF stream or Office part
+const maxDocumentPartSize = 4 * 1024 * 1024
+
+// Extensions of the files downloaded by the document analysis
+var documentExtensions = []string{".pdf", ".docx", ".xlsx", ".pptx", ".jpg", ".jpeg", ".tif", ".tiff"}
+
+var (
+	pdfInfoRegex    = regexp.MustCompile(`/(Author|Creator|Producer)\s*(\((?:\\.|[^\\)])*\)|<[0-9A-Fa-f\s]*>)`)
+	pdfUriRegex     = regexp.MustCompile(`/URI\s*(\((?:\\.|[^\\)])*\)|<[0-9A-Fa-f\s]*>)`)
+	pdfStringRegex  = regexp.MustCompile(`\((?:\\.|[^\\)])*\)`)
+	pdfStreamRegex  = regexp.MustCompile(`(?s)stream\r?\n(.*?)\r?\nendstream`)
+	xmpElementRegex = regexp.MustCompile(`(?s)<(dc:creator|xmp:CreatorTool|pdf:Producer)[^>]*>(.*?)</(?:dc:creator|xmp:CreatorTool|pdf:Producer)>`)
+	xmpAttrRegex    = regexp.MustCompile(`(xmp:CreatorTool|pdf:Producer)="([^"]*)"`)
+	xmlTagRegex     = regexp.MustCompile(`<[^>]*>`)
+	ooxmlFieldRegex = regexp.MustCompile(`(?s)<(dc:creator|cp:lastModifiedBy|Application|AppVersion)>(.*?)</`)
+	ooxmlRelRegex   = regexp.MustCompile(`<Relationship\s[^>]*>`)
+	xmlAttrRegex    = regexp.MustCompile(`(\w+)="([^"]*)"`)
+	docUrlRegex     = regexp.MustCompile(`(?i)\bhttps?://[^\s"'<>()\\]+`)
+
+	// C:\Users\jdoe\report.docx, \\fileserver\share\report.docx, /home/jdoe/report.odt and /Users/jdoe/report.pages
+	docPathRegex     = regexp.MustCompile(`(?i)(?:\b[a-z]:\\|\\\\[a-z0-9_.$-]+\\)[^\r\n\t"*?<>|:]+|/(?:home|Users)/[a-z0-9_.-]+/[^\s"'<>()]*`)
+	docUsernameRegex = regexp.MustCompile(`(?i)(?:\\(?:Users|Documents and Settings)\\|/(?:home|Users)/)([a-z0-9_.-]+)`)
+)
+
+// Usernames found in paths that do not belong to a person
+var genericUsernames = []string{"public", "default", "all users", "administrator", "shared", "guest"}
+
+// EXIF and TIFF tags read from images
+const (
+	exifMake         = 0x010F
+	exifModel        = 0x0110
+	exifSoftware     = 0x0131
+	exifArtist       = 0x013B
+	exifGpsIfd       = 0x8825
+	exifGpsLatRef    = 0x0001
+	exifGpsLat       = 0x0002
+	exifGpsLonRef    = 0x0003
+	exifGpsLon       = 0x0004
+	exifTypeAscii    = 2
+	exifTypeShort    = 3
+	exifTypeLong     = 4
+	exifTypeRational = 5
+)
+
+// Metadata extracted from a document or image
+type DocumentMetadata struct {
+	Urls      []string
+	Authors   []string
+	Software  []string
+	Usernames []string
+	Paths     []string
+	Camera    string
+	Location  string
+}
+
+// Checks if the file is a document or image analyzed for metadata, by its extension
+func IsAnalyzableDocument(filePath string) bool {
+	return shared.SliceContains(documentExtensions, strings.ToLower(path.Ext(filePath)))
+}
+
+// Extracts the embedded URLs, authors, software versions, paths and usernames of a PDF or Office Open XML
+// (DOCX, XLSX, PPTX) document, or the camera and GPS coordinates of a JPEG or TIFF image. The format is
+// detected from the data itself.
+func AnalyzeDocument(data []byte) (DocumentMetadata, error) {
+	var metadata DocumentMetadata
+	var err error
+
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF")):
+		metadata = analyzePdf(data)
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")):
+		metadata, err = analyzeOoxml(data)
+	case bytes.HasPrefix(data, []byte{0xFF, 0xD8}):
+		metadata, err = analyzeJpeg(data)
+	case bytes.HasPrefix(data, []byte("II*\x00")) || bytes.HasPrefix(data, []byte("MM\x00*")):
+		metadata, err = analyzeTiff(data)
+	default:
+		return DocumentMetadata{}, fmt.Errorf(unsupportedDocumentErr)
+	}
+
+	if err != nil {
+		return DocumentMetadata{}, err
+	}
+
+	for _, filePath := range metadata.Paths {
+		for _, match := range docUsernameRegex.FindAllStringSubmatch(filePath, -1) {
+			if !shared.SliceContains(genericUsernames, strings.ToLower(match[1])) {
+				metadata.Usernames = appendUnique(metadata.Usernames, match[1])
+			}
+		}
+	}
+
+	return metadata, nil
+}
+
+func analyzePdf(data []byte) DocumentMetadata {
+	var metadata DocumentMetadata
+
+	// metadata is often kept in compressed object streams, which are scanned along with the raw file
+	segments := []string{string(data)}
+	for _, match := range pdfStreamRegex.FindAllSubmatch(data, -1) {
+		reader, err := zlib.NewReader(bytes.NewReader(match[1]))
+		if err != nil {
+			continue
+		}
+
+		decompressed, _ := io.ReadAll(io.LimitReader(reader, maxDocumentPartSize))
+		reader.Close()
+
+		if len(decompressed) > 0 {
+			segments = append(segments, string(decompressed))
+		}
+	}
+
+	for _, segment := range segments {
+		for _, match := range pdfInfoRegex.FindAllStringSubmatch(segment, -1) {
+			value := decodePdfString(match[2])
+			if match[1] == "Author" {
+				metadata.Authors = appendUnique(metadata.Authors, value)
+			} else {
+				metadata.Software = appendUnique(metadata.Software, value)
+			}
+		}
+
+		for _, match := range pdfUriRegex.FindAllStringSubmatch(segment, -1) {
+			metadata.Urls = appendUnique(metadata.Urls, decodePdfString(match[1]))
+		}
+
+		// XMP packets describe the same properties as the info dictionary
+		for _, match := range xmpElementRegex.FindAllStringSubmatch(segment, -1) {
+			value := html.UnescapeString(xmlTagRegex.ReplaceAllString(match[2], " "))
+			if match[1] == "dc:creator" {
+				metadata.Authors = appendUnique(metadata.Authors, value)
+			} else {
+				metadata.Software = appendUnique(metadata.Software, value)
+			}
+		}
+
+		for _, match := range xmpAttrRegex.FindAllStringSubmatch(segment, -1) {
+			metadata.Software = appendUnique(metadata.Software, html.UnescapeString(match[2]))
+		}
+
+		for _, str := range pdfStringRegex.FindAllString(segment, -1) {
+			metadata.addText(decodePdfString(str))
+		}
+	}
+
+	return metadata
+}
+
+// Decodes a PDF literal string (e.g. "(C:\\report)") or hex string (e.g. "<FEFF0041>"), which are UTF-16 when
+// they start with a byte order mark
+func decodePdfString(str string) string {
+	var raw []byte
+
+	if strings.HasPrefix(str, "<") {
+		digits := strings.Join(strings.Fields(strings.Trim(str, "<>")), "")
+		if len(digits)%2 != 0 {
+			digits += "0"
+		}
+
+		raw, _ = hex.DecodeString(digits)
+	} else {
+		raw = unescapePdfLiteral(strings.TrimSuffix(strings.TrimPrefix(str, "("), ")"))
+	}
+
+	if len(raw) >= 2 && raw[0] == 0xFE && raw[1] == 0xFF {
+		units := make([]uint16, 0, len(raw)/2)
+		for i := 2; i+1 < len(raw); i += 2 {
+			units = append(units, binary.BigEndian.Uint16(raw[i:]))
+		}
+
+		return strings.TrimSpace(string(utf16.Decode(units)))
+	}
+
+	return strings.TrimSpace(string(raw))
+}
+
+func unescapePdfLiteral(str string) []byte {
+	escapes := map[byte]byte{'n': '\n', 'r': '\r', 't': '\t', 'b': '\b', 'f': '\f'}
+
+	var out []byte
+	for i := 0; i < len(str); i++ {
+		if str[i] != '\\' || i+1 == len(str) {
+			out = append(out, str[i])
+			continue
+		}
+
+		i++
+		if escaped, exists := escapes[str[i]]; exists {
+			out = append(out, escaped)
+			continue
+		}
+
+		// octal escapes have up to three digits
+		if str[i] >= '0' && str[i] <= '7' {
+			end := i + 1
+			for end < len(str) && end < i+3 && str[end] >= '0' && str[end] <= '7' {
+				end++
+			}
+
+			value, _ := strconv.ParseUint(str[i:end], 8, 8)
+			out = append(out, byte(value))
+			i = end - 1
+			continue
+		}
+
+		out = append(out, str[i])
+	}
+
+	return out
+}
+
+func analyzeOoxml(data []byte) (DocumentMetadata, error) {
+	var metadata DocumentMetadata
+
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return DocumentMetadata{}, err
+	}
+
+	application, version := "", ""
+
+	for _, file := range reader.File {
+		if !strings.HasSuffix(file.Name, ".xml") && !strings.HasSuffix(file.Name, ".rels") {
+			continue
+		}
+
+		rc, err := file.Open()
+		if err != nil {
+			continue
+		}
+
+		content, _ := io.ReadAll(io.LimitReader(rc, maxDocumentPartSize))
+		rc.Close()
+
+		text := string(content)
+
+		switch file.Name {
+		case "docProps/core.xml", "docProps/app.xml":
+			for _, match := range ooxmlFieldRegex.FindAllStringSubmatch(text, -1) {
+				value := strings.TrimSpace(html.UnescapeString(match[2]))
+
+				switch match[1] {
+				case "Application":
+					application = value
+				case "AppVersion":
+					version = value
+				default:
+					metadata.Authors = appendUnique(metadata.Authors, value)
+				}
+			}
+		}
+
+		// hyperlinks, linked images and attached templates outside the document are external relationships
+		if strings.HasSuffix(file.Name, ".rels") {
+			for _, rel := range ooxmlRelRegex.FindAllString(text, -1) {
+				attrs := map[string]string{}
+				for _, attr := range xmlAttrRegex.FindAllStringSubmatch(rel, -1) {
+					attrs[attr[1]] = html.UnescapeString(attr[2])
+				}
+
+				if attrs["TargetMode"] != "External" {
+					continue
+				}
+
+				target := attrs["Target"]
+				if strings.HasPrefix(strings.ToLower(target), "file:") {
+					unescaped, err := url.PathUnescape(strings.TrimPrefix(strings.TrimPrefix(target, "file:///"), "file:"))
+					if err == nil {
+						target = unescaped
+					}
+
+					metadata.Paths = appendUnique(metadata.Paths, target)
+				} else {
+					metadata.Urls = appendUnique(metadata.Urls, target)
+				}
+			}
+
+			continue
+		}
+
+		metadata.addText(html.UnescapeString(xmlTagRegex.ReplaceAllString(text, " ")))
+	}
+
+	if application != "" {
+		metadata.Software = appendUnique(metadata.Software, strings.TrimSpace(application+" "+version))
+	}
+
+	return metadata, nil
+}
+
+// Finds the EXIF segment of a JPEG image
+func analyzeJpeg(data []byte) (DocumentMetadata, error) {
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return DocumentMetadata{}, fmt.Errorf(invalidExifErr)
+		}
+
+		marker := data[i+1]
+		// the image data follows the start of scan, and no metadata comes after it
+		if marker == 0xDA || marker == 0xD9 {
+			break
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if length < 2 || i+2+length > len(data) {
+			return DocumentMetadata{}, fmt.Errorf(invalidExifErr)
+		}
+
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return analyzeTiff(segment[6:])
+		}
+
+		i += 2 + length
+	}
+
+	return DocumentMetadata{}, nil
+}
+
+// Reads the camera, software, artist and GPS tags of TIFF-structured EXIF data
+func analyzeTiff(data []byte) (DocumentMetadata, error) {
+	if len(data) < 8 {
+		return DocumentMetadata{}, fmt.Errorf(invalidExifErr)
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if data[0] == 'M' {
+		order = binary.BigEndian
+	}
+
+	ifd, err := readIfd(data, order, order.Uint32(data[4:]))
+	if err != nil {
+		return DocumentMetadata{}, err
+	}
+
+	var metadata DocumentMetadata
+
+	cameraMake := ifd.ascii(exifMake)
+	model := ifd.ascii(exifModel)
+	if strings.HasPrefix(strings.ToLower(model), strings.ToLower(cameraMake)) {
+		cameraMake = ""
+	}
+	metadata.Camera = strings.TrimSpace(cameraMake + " " + model)
+
+	metadata.Software = appendUnique(metadata.Software, ifd.ascii(exifSoftware))
+	metadata.Authors = appendUnique(metadata.Authors, ifd.ascii(exifArtist))
+
+	if offset, exists := ifd.long(exifGpsIfd); exists {
+		gps, err := readIfd(data, order, offset)
+		if err != nil {
+			return DocumentMetadata{}, err
+		}
+
+		lat, latOk := gps.coordinate(exifGpsLat, exifGpsLatRef, "S")
+		lon, lonOk := gps.coordinate(exifGpsLon, exifGpsLonRef, "W")
+		if latOk && lonOk {
+			metadata.Location = fmt.Sprintf("%.6f, %.6f", lat, lon)
+		}
+	}
+
+	return metadata, nil
+}
+
+// Entries of an image file directory, with the values resolved from their offsets
+type exifIfd struct {
+	order   binary.ByteOrder
+	entries map[uint16]exifEntry
+}
+
+type exifEntry struct {
+	dataType uint16
+	count    uint32
+	value    []byte
+}
+
+func readIfd(data []byte, order binary.ByteOrder, offset uint32) (exifIfd, error) {
+	ifd := exifIfd{order: order, entries: map[uint16]exifEntry{}}
+
+	if int(offset)+2 > len(data) {
+		return ifd, fmt.Errorf(invalidExifErr)
+	}
+
+	count := int(order.Uint16(data[offset:]))
+	for i := 0; i < count; i++ {
+		start := int(offset) + 2 + i*12
+		if start+12 > len(data) {
+			return ifd, fmt.Errorf(invalidExifErr)
+		}
+
+		entry := exifEntry{dataType: order.Uint16(data[start+2:]), count: order.Uint32(data[start+4:])}
+
+		size := map[uint16]int{exifTypeAscii: 1, exifTypeShort: 2, exifTypeLong: 4, exifTypeRational: 8}[entry.dataType]
+		if size == 0 {
+			continue
+		}
+
+		// values of up to four bytes are stored in the entry itself
+		length := size * int(entry.count)
+		valueStart := start + 8
+		if length > 4 {
+			valueStart = int(order.Uint32(data[start+8:]))
+		}
+
+		if length < 0 || valueStart+length > len(data) {
+			continue
+		}
+
+		entry.value = data[valueStart : valueStart+length]
+		ifd.entries[order.Uint16(data[start:])] = entry
+	}
+
+	return ifd, nil
+}
+
+func (ifd exifIfd) ascii(tag uint16) string {
+	entry, exists := ifd.entries[tag]
+	if !exists || entry.dataType != exifTypeAscii {
+		return ""
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(entry.value), "\x00"))
+}
+
+func (ifd exifIfd) long(tag uint16) (uint32, bool) {
+	entry, exists := ifd.entries[tag]
+	if !exists || entry.count == 0 {
+		return 0, false
+	}
+
+	switch entry.dataType {
+	case exifTypeLong:
+		return ifd.order.Uint32(entry.value), true
+	case exifTypeShort:
+		return uint32(ifd.order.Uint16(entry.value)), true
+	}
+
+	return 0, false
+}
+
+// Converts degrees, minutes and seconds to decimal degrees, negative for the southern or western hemisphere
+func (ifd exifIfd) coordinate(tag uint16, refTag uint16, negativeRef string) (float64, bool) {
+	entry, exists := ifd.entries[tag]
+	if !exists || entry.dataType != exifTypeRational || entry.count != 3 {
+		return 0, false
+	}
+
+	var parts [3]float64
+	for i := range parts {
+		numerator := ifd.order.Uint32(entry.value[i*8:])
+		denominator := ifd.order.Uint32(entry.value[i*8+4:])
+		if denominator == 0 {
+			return 0, false
+		}
+
+		parts[i] = float64(numerator) / float64(denominator)
+	}
+
+	value := parts[0] + parts[1]/60 + parts[2]/3600
+	if strings.EqualFold(ifd.ascii(refTag), negativeRef) {
+		value = -value
+	}
+
+	return math.Round(value*1e6) / 1e6, true
+}
+
+// Collects the URLs and paths mentioned in free text
+func (metadata *DocumentMetadata) addText(text string) {
+	for _, u := range docUrlRegex.FindAllString(text, -1) {
+		metadata.Urls = appendUnique(metadata.Urls, strings.TrimRight(u, ".,;"))
+	}
+
+	for _, filePath := range docPathRegex.FindAllString(text, -1) {
+		metadata.Paths = appendUnique(metadata.Paths, strings.TrimSpace(filePath))
+	}
+}
+
+func appendUnique(slice []string, value string) []string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" || shared.SliceContains(slice, value) {
+		return slice
+	}
+
+	return append(slice, value)
+}
+
+// Downloads every in-scope document and image up to maxSize bytes concurrently, respecting the crawler's thread
+// count and delay, and reports the metadata found in them as findings
+func (crawler *Crawler) AnalyzeDocuments(files []url.URL, maxSize int64) {
+	var targets []url.URL
+	for _, file := range files {
+		if !shared.SameBaseDomain(file.Hostname(), crawler.seedUrl.Hostname()) || !IsAnalyzableDocument(file.Path) {
+			continue
+		}
+
+		targets = append(targets, file)
+	}
+
+	crawler.runConcurrently(len(targets), func(i int) {
+		crawler.analyzeDocument(targets[i], maxSize)
+	})
+}
+
+func (crawler *Crawler) analyzeDocument(target url.URL, maxSize int64) {
+	data, err := crawler.downloadDocument(target, maxSize)
+	if err != nil {
+		return
+	}
+
+	metadata, err := AnalyzeDocument(data)
+	if err != nil {
+		return
+	}
+
+	report := func(findingType shared.FindingType, values ...string) {
+		for _, value := range values {
+			if value == "" {
+				continue
+			}
+
+			finding := shared.Finding{Type: findingType, Value: value, Url: target, Source: shared.Documents}
+			crawler.handleUniqueFinding(finding, target.String()+"|"+string(findingType)+"|"+value)
+		}
+	}
+
+	report(shared.DocumentUrl, metadata.Urls...)
+	report(shared.DocumentAuthor, metadata.Authors...)
+	report(shared.DocumentSoftware, metadata.Software...)
+	report(shared.DocumentUsername, metadata.Usernames...)
+	report(shared.DocumentPath, metadata.Paths...)
+	report(shared.ImageCamera, metadata.Camera)
+	report(shared.ImageLocation, metadata.Location)
+}
+
+// Downloads a file with the crawler's headers and cookies, giving up on files larger than maxSize bytes
+func (crawler *Crawler) downloadDocument(target url.URL, maxSize int64) ([]byte, error) {
+	reqTime := time.Now()
+	defer crawler.waitDelay(reqTime)
+
+	req, err := crawler.newRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(unexpectedStatusErr, target.String(), resp.StatusCode)
+	}
+
+	if resp.ContentLength > maxSize {
+		return nil, fmt.Errorf(documentTooLargeErr)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf(documentTooLargeErr)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,184 @@
+package osint
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeDocumentPdf(t *testing.T) {
+	var stream bytes.Buffer
+	writer := zlib.NewWriter(&stream)
+	writer.Write([]byte(`<x:xmpmeta><rdf:Description xmp:CreatorTool="Microsoft Word 2016"><dc:creator><rdf:Seq><rdf:li>Jane Doe</rdf:li></rdf:Seq></dc:creator></rdf:Description></x:xmpmeta>`))
+	writer.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.7\n")
+	pdf.WriteString("1 0 obj\n<< /Author (Jane Doe) /Producer <FEFF004100630072006F006200610074> /Title (C:\\\\Users\\\\jdoe\\\\Desktop\\\\q3.docx) >>\nendobj\n")
+	pdf.WriteString("2 0 obj\n<< /Type /Annot /A << /S /URI /URI (https://intranet.example.com/wiki) >> >>\nendobj\n")
+	pdf.WriteString("3 0 obj\n<< /Length 0 /Filter /FlateDecode >>\nstream\n")
+	pdf.Write(stream.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF")
+
+	res, err := AnalyzeDocument(pdf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := DocumentMetadata{
+		Urls:      []string{"https://intranet.example.com/wiki"},
+		Authors:   []string{"Jane Doe"},
+		Software:  []string{"Acrobat", "Microsoft Word 2016"},
+		Usernames: []string{"jdoe"},
+		Paths:     []string{`C:\Users\jdoe\Desktop\q3.docx`},
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("AnalyzeDocument expected %+v; got %+v", expected, res)
+	}
+}
+
+func TestAnalyzeDocumentOoxml(t *testing.T) {
+	parts := map[string]string{
+		"docProps/core.xml": `<cp:coreProperties><dc:creator>Jane Doe</dc:creator><cp:lastModifiedBy>John Smith</cp:lastModifiedBy></cp:coreProperties>`,
+		"docProps/app.xml":  `<Properties><Application>Microsoft Office Word</Application><AppVersion>16.0000</AppVersion></Properties>`,
+		"word/_rels/document.xml.rels": `<Relationships>` +
+			`<Relationship Id="rId1" Type="hyperlink" Target="https://portal.example.com/login?a=1&amp;b=2" TargetMode="External"/>` +
+			`<Relationship Id="rId2" Type="styles" Target="styles.xml"/>` +
+			`</Relationships>`,
+		"word/_rels/settings.xml.rels": `<Relationships><Relationship Id="rId1" Type="attachedTemplate" Target="file:///\\fileserver\templates\Normal.dotm" TargetMode="External"/></Relationships>`,
+		"word/document.xml":            `<w:document><w:t>Saved to /home/jsmith/drafts/report.docx</w:t></w:document>`,
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range []string{"docProps/core.xml", "docProps/app.xml", "word/_rels/document.xml.rels", "word/_rels/settings.xml.rels", "word/document.xml"} {
+		w, _ := archive.Create(name)
+		w.Write([]byte(parts[name]))
+	}
+	archive.Close()
+
+	res, err := AnalyzeDocument(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := DocumentMetadata{
+		Urls:      []string{"https://portal.example.com/login?a=1&b=2"},
+		Authors:   []string{"Jane Doe", "John Smith"},
+		Software:  []string{"Microsoft Office Word 16.0000"},
+		Usernames: []string{"jsmith"},
+		Paths:     []string{`\\fileserver\templates\Normal.dotm`, "/home/jsmith/drafts/report.docx"},
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("AnalyzeDocument expected %+v; got %+v", expected, res)
+	}
+}
+
+// Builds a JPEG with a big-endian EXIF segment holding the camera and GPS tags
+func buildExifJpeg() []byte {
+	order := binary.BigEndian
+
+	type entry struct {
+		tag      uint16
+		dataType uint16
+		count    uint32
+		value    []byte
+	}
+
+	rational := func(values ...uint32) []byte {
+		var b []byte
+		for _, v := range values {
+			b = order.AppendUint32(b, v)
+		}
+		return b
+	}
+
+	writeIfd := func(offset int, entries []entry) []byte {
+		ifd := order.AppendUint16(nil, uint16(len(entries)))
+		dataOffset := offset + 2 + len(entries)*12 + 4
+
+		var data []byte
+		for _, e := range entries {
+			ifd = order.AppendUint16(ifd, e.tag)
+			ifd = order.AppendUint16(ifd, e.dataType)
+			ifd = order.AppendUint32(ifd, e.count)
+
+			if len(e.value) <= 4 {
+				ifd = append(ifd, append(e.value, make([]byte, 4-len(e.value))...)...)
+			} else {
+				ifd = order.AppendUint32(ifd, uint32(dataOffset+len(data)))
+				data = append(data, e.value...)
+			}
+		}
+
+		return append(append(ifd, 0, 0, 0, 0), data...)
+	}
+
+	ifd0 := writeIfd(8, []entry{
+		{exifMake, exifTypeAscii, 6, []byte("Canon\x00")},
+		{exifModel, exifTypeAscii, 14, []byte("Canon EOS 80D\x00")},
+		{exifSoftware, exifTypeAscii, 12, []byte("GIMP 2.10.8\x00")},
+		{exifGpsIfd, exifTypeLong, 1, order.AppendUint32(nil, 0)},
+	})
+
+	gpsOffset := 8 + len(ifd0)
+	order.PutUint32(ifd0[2+3*12+8:], uint32(gpsOffset))
+	gps := writeIfd(gpsOffset, []entry{
+		{exifGpsLatRef, exifTypeAscii, 2, []byte("S\x00")},
+		{exifGpsLat, exifTypeRational, 3, rational(33, 1, 51, 1, 3240, 100)},
+		{exifGpsLonRef, exifTypeAscii, 2, []byte("E\x00")},
+		{exifGpsLon, exifTypeRational, 3, rational(151, 1, 12, 1, 3600, 100)},
+	})
+
+	tiff := append([]byte("MM\x00*"), 0, 0, 0, 8)
+	tiff = append(append(tiff, ifd0...), gps...)
+
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	jpeg := []byte{0xFF, 0xD8, 0xFF, 0xE1}
+	jpeg = order.AppendUint16(jpeg, uint16(len(segment)+2))
+	jpeg = append(jpeg, segment...)
+
+	return append(jpeg, 0xFF, 0xDA, 0, 2, 0xFF, 0xD9)
+}
+
+func TestAnalyzeDocumentJpeg(t *testing.T) {
+	res, err := AnalyzeDocument(buildExifJpeg())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := DocumentMetadata{
+		Software: []string{"GIMP 2.10.8"},
+		Camera:   "Canon EOS 80D",
+		Location: "-33.859000, 151.210000",
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("AnalyzeDocument expected %+v; got %+v", expected, res)
+	}
+
+	if _, err := AnalyzeDocument([]byte("<html></html>")); err == nil {
+		t.Errorf("AnalyzeDocument expected error for unsupported format")
+	}
+}
+
+func TestIsAnalyzableDocument(t *testing.T) {
+	cases := map[string]bool{
+		"/files/report.PDF":  true,
+		"/files/budget.xlsx": true,
+		"/img/photo.jpeg":    true,
+		"/index.php":         false,
+		"/files/":            false,
+	}
+
+	for filePath, expected := range cases {
+		if res := IsAnalyzableDocument(filePath); res != expected {
+			t.Errorf("IsAnalyzableDocument(%s) expected %v; got %v", filePath, expected, res)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package osint
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+// Maximum amount of bytes read from a favicon
+const maxFaviconSize = 1024 * 1024
+
+// Icon used by a host, which may be served from another host (e.g. a CDN)
+type iconRef struct {
+	host url.URL
+	icon url.URL
+}
+
+// Hashes of a fetched icon
+type iconHash struct {
+	mmh3 string
+	md5  string
+}
+
+// Computes the favicon hash in Shodan's format: the signed MurmurHash3 (x86, 32-bit) of the base64-encoded
+// bytes, where the base64 string is split in lines of 76 characters as done by Python's base64.encodebytes.
+func FaviconHash(data []byte) string {
+	encoded := base64.StdEncoding.EncodeToString(data)
+
+	var builder strings.Builder
+	for i := 0; i < len(encoded); i += 76 {
+		end := min(i+76, len(encoded))
+		builder.WriteString(encoded[i:end])
+		builder.WriteString("\n")
+	}
+
+	return strconv.Itoa(int(int32(murmur3([]byte(builder.String()), 0))))
+}
+
+// MurmurHash3 x86 32-bit
+func murmur3(data []byte, seed uint32) uint32 {
+	const (
+		c1 = 0xcc9e2d51
+		c2 = 0x1b873593
+	)
+
+	hash := seed
+	blocks := len(data) / 4
+
+	for i := 0; i < blocks; i++ {
+		k := binary.LittleEndian.Uint32(data[i*4:])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+
+		hash ^= k
+		hash = bits.RotateLeft32(hash, 13)
+		hash = hash*5 + 0xe6546b64
+	}
+
+	tail := data[blocks*4:]
+	var k uint32
+	switch len(tail) {
+	case 3:
+		k ^= uint32(tail[2]) << 16
+		fallthrough
+	case 2:
+		k ^= uint32(tail[1]) << 8
+		fallthrough
+	case 1:
+		k ^= uint32(tail[0])
+		k *= c1
+		k = bits.RotateLeft32(k, 15)
+		k *= c2
+		hash ^= k
+	}
+
+	hash ^= uint32(len(data))
+	hash ^= hash >> 16
+	hash *= 0x85ebca6b
+	hash ^= hash >> 13
+	hash *= 0xc2b2ae35
+	hash ^= hash >> 16
+
+	return hash
+}
+
+// Checks if a fetched page is a favicon based on its path or content type
+func isFavicon(pg page) bool {
+	if strings.HasPrefix(path.Base(pg.url.Path), "favicon") {
+		return true
+	}
+
+	contentType := pg.header.Get("Content-Type")
+	return strings.Contains(contentType, "image/x-icon") || strings.Contains(contentType, "image/vnd.microsoft.icon")
+}
+
+// Finds the icons declared in a page with <link rel="icon"> and its variants (e.g. "shortcut icon", "apple-touch-icon")
+func ExtractIconLinks(node *html.Node) []string {
+	var links []string
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "link" {
+			rel := strings.Fields(strings.ToLower(getAttr(node, "rel")))
+			href := getAttr(node, "href")
+
+			for _, value := range rel {
+				if href != "" && (value == "icon" || value == "apple-touch-icon") {
+					links = append(links, href)
+					break
+				}
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(node)
+
+	return links
+}
+
+// Queues the host's /favicon.ico and the icons declared by the page
+func (crawler *Crawler) findIcons(pg page) {
+	crawler.mutex.Lock()
+	defer crawler.mutex.Unlock()
+
+	crawler.queueIcon(pg.url, "/favicon.ico")
+
+	if pg.node == nil {
+		return
+	}
+
+	for _, link := range ExtractIconLinks(pg.node) {
+		crawler.queueIcon(pg.url, link)
+	}
+}
+
+// Queues an icon to be fetched after the current depth is crawled, along with the host of the page using it. Must be
+// called with the crawler's mutex held.
+func (crawler *Crawler) queueIcon(base url.URL, ref string) {
+	refUrl, err := url.Parse(ref)
+	if err != nil {
+		return
+	}
+
+	iconUrl := *base.ResolveReference(refUrl)
+	if iconUrl.Scheme != "http" && iconUrl.Scheme != "https" {
+		return
+	}
+
+	host := url.URL{Scheme: base.Scheme, Host: base.Host}
+	key := host.String() + "|" + iconUrl.String()
+	if _, exists := crawler.iconMap[key]; exists {
+		return
+	}
+
+	crawler.iconMap[key] = struct{}{}
+	crawler.iconQueue = append(crawler.iconQueue, iconRef{host: host, icon: iconUrl})
+}
+
+// Fetches and hashes every queued icon concurrently, respecting the crawler's thread count and delay. Icons shared
+// by several hosts are fetched once, and their hashes are reported under each host using them.
+func (crawler *Crawler) fetchQueuedFavicons() {
+	crawler.mutex.Lock()
+	queue := crawler.iconQueue
+	crawler.iconQueue = []iconRef{}
+	crawler.mutex.Unlock()
+
+	var icons []url.URL
+	hosts := map[string][]url.URL{}
+	for _, ref := range queue {
+		if _, exists := hosts[ref.icon.String()]; !exists {
+			icons = append(icons, ref.icon)
+		}
+
+		hosts[ref.icon.String()] = append(hosts[ref.icon.String()], ref.host)
+	}
+
+	semaphore := make(chan struct{}, crawler.threads)
+	var wg sync.WaitGroup
+
+	for _, iconUrl := range icons {
+		wg.Add(1)
+
+		go func(iconUrl url.URL) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			hash := crawler.hashFavicon(iconUrl)
+			if hash == nil {
+				return
+			}
+
+			for _, host := range hosts[iconUrl.String()] {
+				crawler.reportFavicon(host, *hash)
+			}
+		}(iconUrl)
+	}
+
+	wg.Wait()
+}
+
+// Returns the hashes of an icon, fetching it the first time it is seen. Returns nil if the URL does not serve an icon.
+func (crawler *Crawler) hashFavicon(iconUrl url.URL) *iconHash {
+	crawler.mutex.Lock()
+	hash, exists := crawler.iconHashes[iconUrl.String()]
+	crawler.mutex.Unlock()
+
+	if exists {
+		return hash
+	}
+
+	reqTime := time.Now()
+	defer crawler.waitDelay(reqTime)
+
+	resp, err := crawler.sendRequest(iconUrl)
+	if err != nil {
+		crawler.propagateWarning(err.Error())
+		return nil
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxFaviconSize))
+	if err != nil {
+		crawler.propagateWarning(err.Error())
+		return nil
+	}
+
+	// hosts that serve an HTML page for every path are not serving a favicon
+	if resp.StatusCode == http.StatusOK && len(data) > 0 && !strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+		hash = &iconHash{mmh3: FaviconHash(data), md5: fmt.Sprintf("%x", md5.Sum(data))}
+	}
+
+	crawler.mutex.Lock()
+	crawler.iconHashes[iconUrl.String()] = hash
+	crawler.mutex.Unlock()
+
+	return hash
+}
+
+// Propagates the hashes of an icon used by the host, along with the technologies matching them
+func (crawler *Crawler) reportFavicon(host url.URL, hash iconHash) {
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.FaviconHash,
+		Value:  fmt.Sprintf("mmh3:%s md5:%s", hash.mmh3, hash.md5),
+		Url:    host,
+		Source: shared.Favicon,
+	})
+
+	if crawler.fingerprinter == nil {
+		return
+	}
+
+	for _, tech := range crawler.fingerprinter.Analyze(FingerprintInput{FaviconHash: hash.mmh3}) {
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.Technology,
+			Value:  tech.String(),
+			Url:    host,
+			Source: shared.Fingerprint,
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package osint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+func TestMurmur3(t *testing.T) {
+	cases := []struct {
+		input    string
+		expected uint32
+	}{
+		{"", 0},
+		{"hello", 0x248bfa47},
+		{"The quick brown fox jumps over the lazy dog", 0x2e4ff723},
+	}
+
+	for _, tc := range cases {
+		res := murmur3([]byte(tc.input), 0)
+		if res != tc.expected {
+			t.Errorf("murmur3(%s) expected %x; got %x", tc.input, tc.expected, res)
+		}
+	}
+}
+
+func TestFaviconHash(t *testing.T) {
+	// mmh3.hash("foo") in Python
+	if res := int32(murmur3([]byte("foo"), 0)); res != -156908512 {
+		t.Errorf("murmur3 signed expected -156908512; got %d", res)
+	}
+
+	if res := FaviconHash([]byte{}); res != "0" {
+		t.Errorf("FaviconHash of empty favicon expected 0; got %s", res)
+	}
+
+	// the base64 string is longer than 76 characters and must be split in lines
+	data := make([]byte, 100)
+	for i := range data {
+		data[i] = byte(i)
+	}
+
+	if res := FaviconHash(data); res != "-1165240594" {
+		t.Errorf("FaviconHash expected -1165240594; got %s", res)
+	}
+}
+
+func TestExtractIconLinks(t *testing.T) {
+	page := `<html><head>
+		<link rel="stylesheet" href="/style.css">
+		<link rel="shortcut icon" href="/static/favicon.png">
+		<link rel="apple-touch-icon" href="https://cdn.example.com/touch.png">
+		<link rel="icon">
+	</head></html>`
+
+	node, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/static/favicon.png", "https://cdn.example.com/touch.png"}
+
+	res := ExtractIconLinks(node)
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ExtractIconLinks expected %v; got %v", expected, res)
+	}
+}
+
+func TestSharedFaviconHosts(t *testing.T) {
+	var iconRequests int32
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/icon.png" {
+			http.NotFound(w, r)
+			return
+		}
+
+		atomic.AddInt32(&iconRequests, 1)
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("shared icon"))
+	}))
+	t.Cleanup(cdn.Close)
+
+	page := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/" {
+				http.NotFound(w, r)
+				return
+			}
+
+			w.Write([]byte(`<html><head><link rel="icon" href="` + cdn.URL + `/icon.png"></head><body>` + body + `</body></html>`))
+		}
+	}
+
+	other := httptest.NewServer(page(""))
+	t.Cleanup(other.Close)
+
+	seedServer := httptest.NewServer(page(`<a href="` + other.URL + `/">other</a>`))
+	t.Cleanup(seedServer.Close)
+
+	seed, _ := url.Parse(seedServer.URL)
+	comms := shared.NewCommsChannels()
+
+	crawler := NewCrawler(false, false, *seed, 2, 0, []url.URL{*seed}, 2, comms, map[string]string{}, map[string]string{})
+	crawler.EnableFaviconHashing()
+
+	var hosts []string
+	for _, finding := range collectCrawlFindings(&crawler, comms) {
+		if finding.Type == shared.FaviconHash {
+			hosts = append(hosts, finding.Url.Host)
+		}
+	}
+	sort.Strings(hosts)
+
+	otherUrl, _ := url.Parse(other.URL)
+	expected := []string{seed.Host, otherUrl.Host}
+	sort.Strings(expected)
+
+	// the icon is reported under both hosts using it, but only fetched once
+	if !reflect.DeepEqual(hosts, expected) {
+		t.Errorf("Crawl expected the shared favicon to be reported for %v; got %v", expected, hosts)
+	}
+
+	if count := atomic.LoadInt32(&iconRequests); count != 1 {
+		t.Errorf("Crawl expected the shared favicon to be fetched once; got %d requests", count)
+	}
+}
@@ -0,0 +1,364 @@
+package osint
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Errors
+const (
+	noTechnologiesErr = "signatures file contains no technologies"
+)
+
+var versionGroupRegex = regexp.MustCompile(`\\(\d)`)
+
+// Technology detected on a host
+type Technology struct {
+	Name    string
+	Version string
+}
+
+func (tech Technology) String() string {
+	if tech.Version == "" {
+		return tech.Name
+	}
+
+	return tech.Name + " " + tech.Version
+}
+
+// Data gathered from a single response that the fingerprinter matches against
+type FingerprintInput struct {
+	Header      http.Header
+	Cookies     map[string]string
+	Meta        map[string][]string
+	ScriptSrc   []string
+	Html        string
+	FaviconHash string
+}
+
+// Matches responses against Wappalyzer-compatible signatures
+type Fingerprinter struct {
+	technologies []signature
+}
+
+type signature struct {
+	name      string
+	headers   map[string][]pattern
+	cookies   map[string][]pattern
+	meta      map[string][]pattern
+	scriptSrc []pattern
+	html      []pattern
+	favicon   []string
+	implies   []string
+}
+
+type pattern struct {
+	regex   *regexp.Regexp
+	version string
+}
+
+// Raw representation of a technology in a Wappalyzer technologies file. Fields can either be a string or a list.
+// The "favicon" field is not part of the Wappalyzer format and holds Shodan-style mmh3 favicon hashes.
+type rawSignature struct {
+	Headers   map[string]string `json:"headers"`
+	Cookies   map[string]string `json:"cookies"`
+	Meta      map[string]any    `json:"meta"`
+	ScriptSrc any               `json:"scriptSrc"`
+	Scripts   any               `json:"scripts"`
+	Html      any               `json:"html"`
+	Favicon   any               `json:"favicon"`
+	Implies   any               `json:"implies"`
+}
+
+// Loads a Wappalyzer-compatible signatures file. Both the {"technologies": {...}} layout and a plain map of
+// technologies are accepted. Patterns that cannot be compiled by Go's regex engine are skipped.
+func LoadFingerprinter(path string) (Fingerprinter, error) {
+	bytes, err := os.ReadFile(path)
+	if err != nil {
+		return Fingerprinter{}, err
+	}
+
+	var wrapped struct {
+		Technologies map[string]rawSignature `json:"technologies"`
+	}
+	if err := json.Unmarshal(bytes, &wrapped); err != nil {
+		return Fingerprinter{}, err
+	}
+
+	rawTechnologies := wrapped.Technologies
+	if len(rawTechnologies) == 0 {
+		if err := json.Unmarshal(bytes, &rawTechnologies); err != nil {
+			return Fingerprinter{}, err
+		}
+	}
+
+	if len(rawTechnologies) == 0 {
+		return Fingerprinter{}, fmt.Errorf(noTechnologiesErr)
+	}
+
+	return newFingerprinter(rawTechnologies), nil
+}
+
+func newFingerprinter(rawTechnologies map[string]rawSignature) Fingerprinter {
+	var technologies []signature
+	for name, raw := range rawTechnologies {
+		sig := signature{
+			name:      name,
+			headers:   compilePatternMap(raw.Headers),
+			cookies:   compilePatternMap(raw.Cookies),
+			meta:      map[string][]pattern{},
+			scriptSrc: compilePatterns(append(toStringSlice(raw.ScriptSrc), toStringSlice(raw.Scripts)...)),
+			html:      compilePatterns(toStringSlice(raw.Html)),
+			favicon:   toStringSlice(raw.Favicon),
+		}
+
+		for key, value := range raw.Meta {
+			sig.meta[strings.ToLower(key)] = compilePatterns(toStringSlice(value))
+		}
+
+		for _, implied := range toStringSlice(raw.Implies) {
+			implied, _, _ = strings.Cut(implied, "\\;")
+			sig.implies = append(sig.implies, implied)
+		}
+
+		technologies = append(technologies, sig)
+	}
+
+	// keeps the output stable between runs
+	sort.Slice(technologies, func(i, j int) bool {
+		return technologies[i].name < technologies[j].name
+	})
+
+	return Fingerprinter{technologies: technologies}
+}
+
+// Returns the technologies detected in the input, including the ones implied by them
+func (fp *Fingerprinter) Analyze(input FingerprintInput) []Technology {
+	detected := map[string]string{}
+
+	for _, sig := range fp.technologies {
+		version, matched := sig.match(input)
+		if matched {
+			detected[sig.name] = version
+		}
+	}
+
+	// resolve implied technologies until no new ones are added
+	for added := true; added; {
+		added = false
+		for _, sig := range fp.technologies {
+			if _, exists := detected[sig.name]; !exists {
+				continue
+			}
+
+			for _, implied := range sig.implies {
+				if _, exists := detected[implied]; !exists {
+					detected[implied] = ""
+					added = true
+				}
+			}
+		}
+	}
+
+	var output []Technology
+	for name, version := range detected {
+		output = append(output, Technology{Name: name, Version: version})
+	}
+
+	sort.Slice(output, func(i, j int) bool {
+		return output[i].Name < output[j].Name
+	})
+
+	return output
+}
+
+// Checks every signature field against the input, returning the first version found
+func (sig *signature) match(input FingerprintInput) (string, bool) {
+	matched := false
+	version := ""
+
+	update := func(patterns []pattern, value string) {
+		for _, p := range patterns {
+			if v, ok := p.match(value); ok {
+				matched = true
+				if version == "" {
+					version = v
+				}
+			}
+		}
+	}
+
+	for name, patterns := range sig.headers {
+		if values, exists := input.Header[http.CanonicalHeaderKey(name)]; exists {
+			update(patterns, strings.Join(values, ", "))
+		}
+	}
+
+	for name, patterns := range sig.cookies {
+		for cookieName, value := range input.Cookies {
+			if strings.EqualFold(cookieName, name) {
+				update(patterns, value)
+			}
+		}
+	}
+
+	for name, patterns := range sig.meta {
+		for _, content := range input.Meta[name] {
+			update(patterns, content)
+		}
+	}
+
+	for _, src := range input.ScriptSrc {
+		update(sig.scriptSrc, src)
+	}
+
+	if input.Html != "" {
+		update(sig.html, input.Html)
+	}
+
+	if input.FaviconHash != "" {
+		for _, hash := range sig.favicon {
+			if hash == input.FaviconHash {
+				matched = true
+			}
+		}
+	}
+
+	return version, matched
+}
+
+// Returns the resolved version template if the pattern matches the value
+func (p *pattern) match(value string) (string, bool) {
+	groups := p.regex.FindStringSubmatch(value)
+	if groups == nil {
+		return "", false
+	}
+
+	if p.version == "" {
+		return "", true
+	}
+
+	version := versionGroupRegex.ReplaceAllStringFunc(p.version, func(ref string) string {
+		index, _ := strconv.Atoi(ref[1:])
+		if index >= len(groups) {
+			return ""
+		}
+
+		return groups[index]
+	})
+
+	return strings.TrimSpace(version), true
+}
+
+// Collects the fingerprinting data available in a response and its parsed HTML
+func NewFingerprintInput(header http.Header, body []byte, node *html.Node) FingerprintInput {
+	input := FingerprintInput{
+		Header:  header,
+		Cookies: map[string]string{},
+		Meta:    map[string][]string{},
+		Html:    string(body),
+	}
+
+	for _, cookie := range (&http.Response{Header: header}).Cookies() {
+		input.Cookies[cookie.Name] = cookie.Value
+	}
+
+	if node == nil {
+		return input
+	}
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "meta" {
+			name := strings.ToLower(getAttr(node, "name"))
+			if name == "" {
+				name = strings.ToLower(getAttr(node, "property"))
+			}
+
+			if name != "" {
+				input.Meta[name] = append(input.Meta[name], getAttr(node, "content"))
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(node)
+
+	input.ScriptSrc = ExtractScriptSources(node)
+
+	return input
+}
+
+// Compiles Wappalyzer patterns, where the regex and its tags are separated by "\;" (e.g. "^nginx(?:/([\d.]+))?\;version:\1")
+func compilePatterns(rawPatterns []string) []pattern {
+	var patterns []pattern
+	for _, raw := range rawPatterns {
+		parts := strings.Split(raw, "\\;")
+
+		regex, err := regexp.Compile("(?i)" + parts[0])
+		if err != nil {
+			continue
+		}
+
+		p := pattern{regex: regex}
+		for _, tag := range parts[1:] {
+			if value, found := strings.CutPrefix(tag, "version:"); found {
+				p.version = value
+			}
+		}
+
+		patterns = append(patterns, p)
+	}
+
+	return patterns
+}
+
+func compilePatternMap(raw map[string]string) map[string][]pattern {
+	output := map[string][]pattern{}
+	for key, value := range raw {
+		output[strings.ToLower(key)] = compilePatterns([]string{value})
+	}
+
+	return output
+}
+
+// Converts a JSON value that can either be a string or a list of strings
+func toStringSlice(value any) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []any:
+		var output []string
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				output = append(output, str)
+			}
+		}
+		return output
+	case float64:
+		return []string{strconv.FormatFloat(v, 'f', -1, 64)}
+	}
+
+	return nil
+}
+
+func getAttr(node *html.Node, key string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == key {
+			return attr.Val
+		}
+	}
+
+	return ""
+}
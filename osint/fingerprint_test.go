@@ -0,0 +1,64 @@
+package osint
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/html"
+)
+
+func TestFingerprinterAnalyze(t *testing.T) {
+	fp := newFingerprinter(map[string]rawSignature{
+		"Nginx": {
+			Headers: map[string]string{"Server": `nginx(?:/([\d.]+))?\;version:\1`},
+		},
+		"WordPress": {
+			Meta:      map[string]any{"generator": `^WordPress ?([\d.]+)?\;version:\1`},
+			ScriptSrc: []any{"/wp-includes/"},
+			Implies:   []any{"PHP", `MySQL\;confidence:50`},
+		},
+		"PHP": {
+			Cookies: map[string]string{"PHPSESSID": ""},
+		},
+		"MySQL": {},
+		"jQuery": {
+			ScriptSrc: `jquery[.-]([\d.]*\d)[^/]*\.js\;version:\1`,
+		},
+		"Grafana": {
+			Favicon: "-1234",
+		},
+	})
+
+	header := http.Header{}
+	header.Set("Server", "nginx/1.18.0")
+
+	body := `<html><head>
+		<meta name="generator" content="WordPress 6.4.2">
+		<script src="/wp-includes/js/jquery/jquery-3.7.1.min.js"></script>
+	</head></html>`
+
+	node, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Technology{
+		{Name: "MySQL"},
+		{Name: "Nginx", Version: "1.18.0"},
+		{Name: "PHP"},
+		{Name: "WordPress", Version: "6.4.2"},
+		{Name: "jQuery", Version: "3.7.1"},
+	}
+
+	res := fp.Analyze(NewFingerprintInput(header, []byte(body), node))
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("Analyze expected %v; got %v", expected, res)
+	}
+
+	res = fp.Analyze(FingerprintInput{FaviconHash: "-1234"})
+	if !reflect.DeepEqual(res, []Technology{{Name: "Grafana"}}) {
+		t.Errorf("Analyze expected Grafana from favicon hash; got %v", res)
+	}
+}
@@ -0,0 +1,149 @@
+package osint
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// HSTS max-age below 180 days is considered weak
+const minHstsMaxAge = 15552000
+
+// Response headers recorded by the audit, in the order they are displayed
+var AuditedHeaders = []string{
+	"Content-Security-Policy",
+	"Strict-Transport-Security",
+	"X-Frame-Options",
+	"Referrer-Policy",
+	"Permissions-Policy",
+	"Access-Control-Allow-Origin",
+}
+
+var (
+	idSegmentRegex = regexp.MustCompile(`^(?:\d+|[0-9a-fA-F]{16,}|[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})$`)
+	maxAgeRegex    = regexp.MustCompile(`(?i)max-age\s*=\s*"?(\d+)`)
+)
+
+// Result of the passive audit of a single response
+type HeaderAudit struct {
+	Host    string
+	Pattern string
+	Values  map[string]string
+	Issues  []HeaderIssue
+}
+
+type HeaderIssue struct {
+	Type  shared.FindingType
+	Value string
+}
+
+// Audits the security headers, CORS policy and cookie flags of a response. HSTS and the Secure cookie flag
+// are only expected when the response was served over HTTPS.
+func AuditHeaders(header http.Header, secure bool) HeaderAudit {
+	audit := HeaderAudit{Values: map[string]string{}}
+
+	for _, name := range AuditedHeaders {
+		if value := strings.Join(header.Values(name), ", "); value != "" {
+			audit.Values[name] = value
+		}
+	}
+
+	addIssue := func(issueType shared.FindingType, value string) {
+		audit.Issues = append(audit.Issues, HeaderIssue{Type: issueType, Value: value})
+	}
+
+	csp, hasCsp := audit.Values["Content-Security-Policy"]
+	if !hasCsp {
+		addIssue(shared.MissingHeader, "Content-Security-Policy")
+	} else {
+		for _, directive := range []string{"'unsafe-inline'", "'unsafe-eval'"} {
+			if strings.Contains(csp, directive) {
+				addIssue(shared.WeakHeader, "Content-Security-Policy allows "+directive)
+			}
+		}
+	}
+
+	if secure {
+		hsts, hasHsts := audit.Values["Strict-Transport-Security"]
+		if !hasHsts {
+			addIssue(shared.MissingHeader, "Strict-Transport-Security")
+		} else if match := maxAgeRegex.FindStringSubmatch(hsts); match == nil {
+			addIssue(shared.WeakHeader, "Strict-Transport-Security has no max-age")
+		} else if maxAge, _ := strconv.Atoi(match[1]); maxAge < minHstsMaxAge {
+			addIssue(shared.WeakHeader, "Strict-Transport-Security max-age is "+match[1])
+		}
+	}
+
+	// frame-ancestors supersedes X-Frame-Options
+	if _, hasXfo := audit.Values["X-Frame-Options"]; !hasXfo && !strings.Contains(csp, "frame-ancestors") {
+		addIssue(shared.MissingHeader, "X-Frame-Options")
+	}
+
+	referrer, hasReferrer := audit.Values["Referrer-Policy"]
+	if !hasReferrer {
+		addIssue(shared.MissingHeader, "Referrer-Policy")
+	} else if strings.Contains(strings.ToLower(referrer), "unsafe-url") {
+		addIssue(shared.WeakHeader, "Referrer-Policy is unsafe-url")
+	}
+
+	if _, hasPermissions := audit.Values["Permissions-Policy"]; !hasPermissions {
+		addIssue(shared.MissingHeader, "Permissions-Policy")
+	}
+
+	if origin, hasCors := audit.Values["Access-Control-Allow-Origin"]; hasCors {
+		credentials := strings.EqualFold(header.Get("Access-Control-Allow-Credentials"), "true")
+
+		switch {
+		case origin == "*" && credentials:
+			addIssue(shared.PermissiveCors, "Access-Control-Allow-Origin is * with credentials")
+		case origin == "*":
+			addIssue(shared.PermissiveCors, "Access-Control-Allow-Origin is *")
+		case strings.EqualFold(origin, "null"):
+			addIssue(shared.PermissiveCors, "Access-Control-Allow-Origin is null")
+		}
+	}
+
+	for _, cookie := range (&http.Response{Header: header}).Cookies() {
+		var missing []string
+		if secure && !cookie.Secure {
+			missing = append(missing, "Secure")
+		}
+
+		if !cookie.HttpOnly {
+			missing = append(missing, "HttpOnly")
+		}
+
+		// the zero value means the attribute is absent
+		if cookie.SameSite == 0 || cookie.SameSite == http.SameSiteDefaultMode {
+			missing = append(missing, "SameSite")
+		} else if cookie.SameSite == http.SameSiteNoneMode && !cookie.Secure && !secure {
+			missing = append(missing, "Secure (required by SameSite=None)")
+		}
+
+		if len(missing) > 0 {
+			addIssue(shared.InsecureCookie, cookie.Name+" missing "+strings.Join(missing, ", "))
+		}
+	}
+
+	return audit
+}
+
+// Replaces path segments that look like IDs (numbers, hashes and UUIDs) with a placeholder, so pages
+// generated by the same route are audited once (e.g. /users/42/posts -> /users/{id}/posts)
+func PathPattern(path string) string {
+	if path == "" {
+		return "/"
+	}
+
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if idSegmentRegex.MatchString(segment) {
+			segments[i] = "{id}"
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,99 @@
+package osint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+func TestAuditHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Content-Security-Policy", "default-src 'self'; script-src 'unsafe-inline'; frame-ancestors 'none'")
+	header.Set("Strict-Transport-Security", "max-age=3600")
+	header.Set("Referrer-Policy", "no-referrer")
+	header.Set("Access-Control-Allow-Origin", "*")
+	header.Add("Set-Cookie", "session=abc; Path=/; Secure; HttpOnly; SameSite=Strict")
+	header.Add("Set-Cookie", "tracking=xyz; Path=/")
+
+	expected := []HeaderIssue{
+		{shared.WeakHeader, "Content-Security-Policy allows 'unsafe-inline'"},
+		{shared.WeakHeader, "Strict-Transport-Security max-age is 3600"},
+		{shared.MissingHeader, "Permissions-Policy"},
+		{shared.PermissiveCors, "Access-Control-Allow-Origin is *"},
+		{shared.InsecureCookie, "tracking missing Secure, HttpOnly, SameSite"},
+	}
+
+	audit := AuditHeaders(header, true)
+	if !reflect.DeepEqual(audit.Issues, expected) {
+		t.Errorf("AuditHeaders expected %v; got %v", expected, audit.Issues)
+	}
+
+	if audit.Values["Referrer-Policy"] != "no-referrer" {
+		t.Errorf("AuditHeaders expected Referrer-Policy value no-referrer; got %s", audit.Values["Referrer-Policy"])
+	}
+
+	// HSTS is not expected over plain HTTP
+	audit = AuditHeaders(http.Header{}, false)
+	for _, issue := range audit.Issues {
+		if issue.Value == "Strict-Transport-Security" {
+			t.Errorf("AuditHeaders expected no HSTS issue over HTTP")
+		}
+	}
+}
+
+func TestPathPattern(t *testing.T) {
+	cases := map[string]string{
+		"":                "/",
+		"/":               "/",
+		"/users/42/posts": "/users/{id}/posts",
+		"/files/d41d8cd98f00b204e9800998ecf8427e":       "/files/{id}",
+		"/orders/123e4567-e89b-12d3-a456-426614174000/": "/orders/{id}/",
+		"/about": "/about",
+	}
+
+	for path, expected := range cases {
+		if res := PathPattern(path); res != expected {
+			t.Errorf("PathPattern(%s) expected %s; got %s", path, expected, res)
+		}
+	}
+}
+
+func TestCrawlerHeaderAuditOncePerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+		w.Write([]byte(`<html><body><a href="/about">about</a><a href="/contact">contact</a></body></html>`))
+	}))
+	t.Cleanup(server.Close)
+
+	seed, _ := url.Parse(server.URL)
+	comms := shared.NewCommsChannels()
+
+	crawler := NewCrawler(false, true, *seed, 2, 0, []url.URL{*seed}, 2, comms, map[string]string{}, map[string]string{})
+	crawler.EnableHeaderAudit()
+
+	counts := map[string]int{}
+	for _, finding := range collectCrawlFindings(&crawler, comms) {
+		if finding.Source == shared.HeaderAudit {
+			counts[finding.Value]++
+		}
+	}
+
+	expected := map[string]int{
+		"Content-Security-Policy":            1,
+		"X-Frame-Options":                    1,
+		"Referrer-Policy":                    1,
+		"Permissions-Policy":                 1,
+		"session missing HttpOnly, SameSite": 1,
+	}
+	if !reflect.DeepEqual(counts, expected) {
+		t.Errorf("header audit expected issues %v; got %v", expected, counts)
+	}
+
+	if audits := crawler.HeaderAudits(); len(audits) != 3 {
+		t.Errorf("header audit expected 3 audited patterns; got %d", len(audits))
+	}
+}
@@ -0,0 +1,392 @@
+package osint
+
+import (
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Selectors commonly used by mail providers and mail servers for their DKIM keys
+var DkimSelectors = []string{
+	"default", "dkim", "mail", "email", "selector1", "selector2", "google", "k1", "k2", "k3", "s1", "s2", "smtp",
+	"key1", "key2", "sig1", "mandrill", "mailjet", "sendgrid", "smtpapi", "amazonses", "mxvault", "zoho", "zmail",
+	"protonmail", "protonmail2", "protonmail3", "fm1", "fm2", "fm3", "pm", "everlytickey1", "everlytickey2", "mta",
+}
+
+// Maximum amount of domains whose SPF records are followed, so large include trees and loops do not run forever
+const maxSpfDomains = 50
+
+// Mechanism or modifier of an SPF record (e.g. include:_spf.example.net, ip4:192.0.2.0/24, mx/24)
+type SpfTerm struct {
+	Qualifier string
+	Name      string
+	Value     string
+	Cidr4     int
+	Cidr6     int
+}
+
+// Domain, address or IP range referenced by the mail records of a domain, along with the mechanism or tag that
+// referenced it (e.g. include, ip4, rua) and the domain whose record did
+type MailLead struct {
+	Value     string
+	Mechanism string
+	Origin    string
+}
+
+// SPF, DMARC and DKIM records of a domain, along with the related domains, IP ranges and report addresses they leak
+type MailPolicy struct {
+	Domain  string
+	Records []ZoneRecord
+	Domains []MailLead
+	Cidrs   []MailLead
+	Reports []MailLead
+}
+
+// Follows the SPF record of the domain through its include and redirect mechanisms, resolving the a and mx
+// mechanisms to IP ranges, and parses the report addresses of its DMARC record. DKIM keys are looked up under the
+// common selectors, and the selectors that are CNAMEs to a provider are reported as related domains.
+func (resolver *SubdomainResolver) AnalyzeMail(domain string) MailPolicy {
+	policy := MailPolicy{Domain: strings.TrimSuffix(strings.ToLower(domain), ".")}
+
+	resolver.followSpf(&policy, policy.Domain, map[string]struct{}{})
+	resolver.collectDmarc(&policy)
+	resolver.collectDkim(&policy)
+
+	return policy
+}
+
+func (resolver *SubdomainResolver) followSpf(policy *MailPolicy, domain string, visited map[string]struct{}) {
+	if _, exists := visited[domain]; exists || len(visited) >= maxSpfDomains {
+		return
+	}
+	visited[domain] = struct{}{}
+
+	record, ok := resolver.policyRecord(domain, "v=spf1")
+	if !ok {
+		return
+	}
+	policy.Records = append(policy.Records, record)
+
+	for _, term := range ParseSpf(record.Rdata, domain) {
+		// macros (e.g. %{i}._spf.example.com) are expanded per message, so they cannot be followed
+		if strings.Contains(term.Value, "%") {
+			continue
+		}
+
+		switch term.Name {
+		case "include", "redirect":
+			policy.addDomain(term.Value, term.Name, domain)
+			resolver.followSpf(policy, term.Value, visited)
+		case "exists", "ptr":
+			if term.Value != domain {
+				policy.addDomain(term.Value, term.Name, domain)
+			}
+		case "a":
+			if term.Value != domain {
+				policy.addDomain(term.Value, term.Name, domain)
+			}
+
+			for _, address := range resolver.addresses(term.Value) {
+				policy.addCidr(addressCidr(address, term), term.Name, domain)
+			}
+		case "mx":
+			if term.Value != domain {
+				policy.addDomain(term.Value, term.Name, domain)
+			}
+
+			for _, exchange := range resolver.mailExchanges(term.Value) {
+				policy.addDomain(exchange, term.Name, domain)
+
+				for _, address := range resolver.addresses(exchange) {
+					policy.addCidr(addressCidr(address, term), term.Name, domain)
+				}
+			}
+		case "ip4", "ip6":
+			if cidr := normalizeCidr(term.Value); cidr != "" {
+				policy.addCidr(cidr, term.Name, domain)
+			}
+		}
+	}
+}
+
+func (resolver *SubdomainResolver) collectDmarc(policy *MailPolicy) {
+	record, ok := resolver.policyRecord("_dmarc."+policy.Domain, "v=dmarc1")
+	if !ok {
+		return
+	}
+	policy.Records = append(policy.Records, record)
+
+	tags := ParseDmarc(record.Rdata)
+	for _, tag := range []string{"rua", "ruf"} {
+		for _, uri := range strings.Split(tags[tag], ",") {
+			address := dmarcAddress(uri)
+			if address == "" {
+				continue
+			}
+
+			if !containsLead(policy.Reports, address) {
+				policy.Reports = append(policy.Reports, MailLead{Value: address, Mechanism: tag, Origin: policy.Domain})
+			}
+
+			_, host, _ := strings.Cut(address, "@")
+			if host != policy.Domain {
+				policy.addDomain(host, tag, policy.Domain)
+			}
+		}
+	}
+}
+
+func (resolver *SubdomainResolver) collectDkim(policy *MailPolicy) {
+	// domains answering every selector would report every one of them
+	if _, ok := resolver.dkimRecord(randomPath(), policy.Domain); ok {
+		return
+	}
+
+	keys := make([]*dkimKey, len(DkimSelectors))
+
+	resolver.runWorkers(len(DkimSelectors), func(i int) {
+		if key, ok := resolver.dkimRecord(DkimSelectors[i], policy.Domain); ok {
+			keys[i] = &key
+		}
+	})
+
+	for _, key := range keys {
+		if key == nil {
+			continue
+		}
+		policy.Records = append(policy.Records, key.record)
+
+		// selectors delegated to a provider are reported once per provider instead of once per selector
+		for _, target := range key.cnames {
+			if target != policy.Domain && !strings.HasSuffix(target, "."+policy.Domain) {
+				policy.addDomain(shared.BaseDomain(target), "dkim", policy.Domain)
+			}
+		}
+	}
+}
+
+// DKIM key of a selector, along with the CNAMEs that led to it
+type dkimKey struct {
+	record ZoneRecord
+	cnames []string
+}
+
+func (resolver *SubdomainResolver) dkimRecord(selector string, domain string) (dkimKey, bool) {
+	res, server, err := resolver.queryWithServer(selector+"._domainkey."+domain, dns.TypeTXT)
+	if err != nil {
+		return dkimKey{}, false
+	}
+
+	var key dkimKey
+	found := false
+	for _, rr := range res.Answer {
+		switch record := rr.(type) {
+		case *dns.CNAME:
+			key.cnames = append(key.cnames, strings.ToLower(strings.TrimSuffix(record.Target, ".")))
+		case *dns.TXT:
+			txt := strings.Join(record.Txt, "")
+			if strings.Contains(strings.ToLower(txt), "v=dkim1") || strings.Contains(txt, "p=") {
+				key.record = newZoneRecord(rr, server)
+				key.record.Rdata = txt
+				found = true
+			}
+		}
+	}
+
+	return key, found
+}
+
+// Returns the TXT record of the name that starts with the version tag (e.g. "v=spf1"), with its strings joined
+func (resolver *SubdomainResolver) policyRecord(name string, version string) (ZoneRecord, bool) {
+	res, server, err := resolver.queryWithServer(name, dns.TypeTXT)
+	if err != nil {
+		return ZoneRecord{}, false
+	}
+
+	for _, rr := range res.Answer {
+		txt, ok := rr.(*dns.TXT)
+		if !ok || !strings.EqualFold(txt.Hdr.Name, dns.Fqdn(name)) {
+			continue
+		}
+
+		joined := strings.Join(txt.Txt, "")
+		fields := strings.FieldsFunc(strings.ToLower(joined), func(r rune) bool { return r == ' ' || r == ';' })
+		if len(fields) == 0 || fields[0] != version {
+			continue
+		}
+
+		record := newZoneRecord(rr, server)
+		record.Rdata = joined
+
+		return record, true
+	}
+
+	return ZoneRecord{}, false
+}
+
+func (resolver *SubdomainResolver) mailExchanges(name string) []string {
+	res, err := resolver.query(name, dns.TypeMX)
+	if err != nil {
+		return []string{}
+	}
+
+	var exchanges []string
+	for _, rr := range res.Answer {
+		if mx, ok := rr.(*dns.MX); ok && mx.Mx != "." {
+			exchanges = append(exchanges, strings.ToLower(strings.TrimSuffix(mx.Mx, ".")))
+		}
+	}
+
+	return exchanges
+}
+
+// Parses the mechanisms and modifiers of an SPF record. Mechanisms without a domain (e.g. "a", "mx/24") are given
+// the domain the record belongs to.
+func ParseSpf(record string, domain string) []SpfTerm {
+	fields := strings.Fields(record)
+	if len(fields) == 0 || !strings.EqualFold(fields[0], "v=spf1") {
+		return []SpfTerm{}
+	}
+
+	var terms []SpfTerm
+	for _, field := range fields[1:] {
+		term := SpfTerm{Qualifier: "+", Cidr4: 32, Cidr6: 128}
+
+		if strings.ContainsAny(field[:1], "+-~?") {
+			term.Qualifier = field[:1]
+			field = field[1:]
+		}
+
+		// modifiers are separated from their value by "=", and mechanisms by ":" or their prefix length
+		name, rest := field, ""
+		if i := strings.IndexAny(field, ":=/"); i >= 0 {
+			name, rest = field[:i], field[i:]
+		}
+		term.Name = strings.ToLower(name)
+
+		value := strings.TrimLeft(rest, ":=")
+		if term.Name == "a" || term.Name == "mx" {
+			value = splitSpfCidr(rest, &term)
+		}
+
+		term.Value = strings.ToLower(strings.TrimSuffix(value, "."))
+		if term.Value == "" && (term.Name == "a" || term.Name == "mx" || term.Name == "ptr") {
+			term.Value = strings.ToLower(strings.TrimSuffix(domain, "."))
+		}
+
+		terms = append(terms, term)
+	}
+
+	return terms
+}
+
+// Splits the prefix lengths off the rest of an a or mx mechanism (e.g. ":example.com/24//64"), returning its domain
+func splitSpfCidr(field string, term *SpfTerm) string {
+	field, cidr6, hasCidr6 := strings.Cut(field, "//")
+	if hasCidr6 {
+		if prefix, err := strconv.Atoi(cidr6); err == nil && prefix >= 0 && prefix <= 128 {
+			term.Cidr6 = prefix
+		}
+	}
+
+	if i := strings.Index(field, "/"); i >= 0 {
+		if prefix, err := strconv.Atoi(field[i+1:]); err == nil && prefix >= 0 && prefix <= 32 {
+			term.Cidr4 = prefix
+		}
+		field = field[:i]
+	}
+
+	return strings.TrimPrefix(field, ":")
+}
+
+// Parses the tags of a DMARC record (e.g. "v=DMARC1; p=reject; rua=mailto:dmarc@example.com") into a map of
+// lowercase tag names to their values
+func ParseDmarc(record string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(record, ";") {
+		name, value, found := strings.Cut(part, "=")
+		if !found {
+			continue
+		}
+
+		tags[strings.ToLower(strings.TrimSpace(name))] = strings.TrimSpace(value)
+	}
+
+	return tags
+}
+
+// Returns the address of a DMARC report URI (e.g. "mailto:dmarc@example.com!10m" -> "dmarc@example.com")
+func dmarcAddress(uri string) string {
+	uri = strings.TrimSpace(uri)
+	if len(uri) < len("mailto:") || !strings.EqualFold(uri[:len("mailto:")], "mailto:") {
+		return ""
+	}
+
+	address, _, _ := strings.Cut(uri[len("mailto:"):], "!")
+	if !strings.Contains(address, "@") {
+		return ""
+	}
+
+	return strings.ToLower(address)
+}
+
+// Returns the range of the address under the prefix length of the mechanism (e.g. 192.0.2.10 with mx/24 ->
+// 192.0.2.0/24)
+func addressCidr(address string, term SpfTerm) string {
+	ip := net.ParseIP(address)
+	if ip == nil {
+		return ""
+	}
+
+	if ip4 := ip.To4(); ip4 != nil {
+		network := net.IPNet{IP: ip4.Mask(net.CIDRMask(term.Cidr4, 32)), Mask: net.CIDRMask(term.Cidr4, 32)}
+		return network.String()
+	}
+
+	network := net.IPNet{IP: ip.Mask(net.CIDRMask(term.Cidr6, 128)), Mask: net.CIDRMask(term.Cidr6, 128)}
+	return network.String()
+}
+
+// Returns the range of an ip4 or ip6 mechanism, treating single addresses as ranges of one address
+func normalizeCidr(value string) string {
+	if _, network, err := net.ParseCIDR(value); err == nil {
+		return network.String()
+	}
+
+	ip := net.ParseIP(value)
+	if ip == nil {
+		return ""
+	}
+
+	if ip.To4() != nil {
+		return addressCidr(value, SpfTerm{Cidr4: 32})
+	}
+
+	return addressCidr(value, SpfTerm{Cidr6: 128})
+}
+
+func (policy *MailPolicy) addDomain(domain string, mechanism string, origin string) {
+	if domain != "" && domain != policy.Domain && !containsLead(policy.Domains, domain) {
+		policy.Domains = append(policy.Domains, MailLead{Value: domain, Mechanism: mechanism, Origin: origin})
+	}
+}
+
+func (policy *MailPolicy) addCidr(cidr string, mechanism string, origin string) {
+	if cidr != "" && !containsLead(policy.Cidrs, cidr) {
+		policy.Cidrs = append(policy.Cidrs, MailLead{Value: cidr, Mechanism: mechanism, Origin: origin})
+	}
+}
+
+func containsLead(leads []MailLead, value string) bool {
+	for _, lead := range leads {
+		if lead.Value == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,121 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseSpf(t *testing.T) {
+	testCases := []struct {
+		record   string
+		expected []SpfTerm
+	}{
+		{
+			record: "v=spf1 include:_spf.example.net ip4:192.0.2.0/24 -all",
+			expected: []SpfTerm{
+				{Qualifier: "+", Name: "include", Value: "_spf.example.net", Cidr4: 32, Cidr6: 128},
+				{Qualifier: "+", Name: "ip4", Value: "192.0.2.0/24", Cidr4: 32, Cidr6: 128},
+				{Qualifier: "-", Name: "all", Cidr4: 32, Cidr6: 128},
+			},
+		},
+		{
+			record: "v=spf1 a mx/24 ~a:mail.example.net/28//64 ip6:2001:db8::/32 redirect=_spf.example.org",
+			expected: []SpfTerm{
+				{Qualifier: "+", Name: "a", Value: "example.com", Cidr4: 32, Cidr6: 128},
+				{Qualifier: "+", Name: "mx", Value: "example.com", Cidr4: 24, Cidr6: 128},
+				{Qualifier: "~", Name: "a", Value: "mail.example.net", Cidr4: 28, Cidr6: 64},
+				{Qualifier: "+", Name: "ip6", Value: "2001:db8::/32", Cidr4: 32, Cidr6: 128},
+				{Qualifier: "+", Name: "redirect", Value: "_spf.example.org", Cidr4: 32, Cidr6: 128},
+			},
+		},
+		{
+			record:   "google-site-verification=abc",
+			expected: []SpfTerm{},
+		},
+	}
+
+	for _, tc := range testCases {
+		res := ParseSpf(tc.record, "example.com")
+		if !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("ParseSpf expected %+v; got %+v", tc.expected, res)
+		}
+	}
+}
+
+func TestParseDmarc(t *testing.T) {
+	res := ParseDmarc("v=DMARC1; p=reject; RUA=mailto:dmarc@example.com,mailto:reports@example.net!10m")
+
+	expected := map[string]string{
+		"v":   "DMARC1",
+		"p":   "reject",
+		"rua": "mailto:dmarc@example.com,mailto:reports@example.net!10m",
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseDmarc expected %v; got %v", expected, res)
+	}
+}
+
+func TestAnalyzeMail(t *testing.T) {
+	addr := startStubResolver(t,
+		`example.com. 300 IN TXT "v=spf1 mx/24 include:_spf.example.net " "ip4:198.51.100.7 ~all"`,
+		`example.com. 300 IN TXT "google-site-verification=abc"`,
+		"example.com. 300 IN MX 10 mail.example.com.",
+		"mail.example.com. 300 IN A 192.0.2.10",
+		`_spf.example.net. 300 IN TXT "v=spf1 ip6:2001:db8::/32 include:example.com -all"`,
+		`_dmarc.example.com. 300 IN TXT "v=DMARC1; p=none; rua=mailto:dmarc@example.com; ruf=mailto:forensics@reports.example.org!10m"`,
+		`selector1._domainkey.example.com. 300 IN CNAME selector1-example-com._domainkey.tenant.onmicrosoft.com.`,
+		`selector1-example-com._domainkey.tenant.onmicrosoft.com. 300 IN TXT "v=DKIM1; k=rsa; p=MIGf"`,
+		`google._domainkey.example.com. 300 IN TXT "v=DKIM1; k=rsa; p=MIIB"`,
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 2, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := resolver.AnalyzeMail("example.com")
+
+	expectedDomains := []MailLead{
+		{Value: "mail.example.com", Mechanism: "mx", Origin: "example.com"},
+		{Value: "_spf.example.net", Mechanism: "include", Origin: "example.com"},
+		{Value: "reports.example.org", Mechanism: "ruf", Origin: "example.com"},
+		{Value: "onmicrosoft.com", Mechanism: "dkim", Origin: "example.com"},
+	}
+	if !reflect.DeepEqual(res.Domains, expectedDomains) {
+		t.Errorf("AnalyzeMail expected domains %v; got %v", expectedDomains, res.Domains)
+	}
+
+	// includes are followed as they are found, before the mechanisms after them
+	expectedCidrs := []MailLead{
+		{Value: "192.0.2.0/24", Mechanism: "mx", Origin: "example.com"},
+		{Value: "2001:db8::/32", Mechanism: "ip6", Origin: "_spf.example.net"},
+		{Value: "198.51.100.7/32", Mechanism: "ip4", Origin: "example.com"},
+	}
+	if !reflect.DeepEqual(res.Cidrs, expectedCidrs) {
+		t.Errorf("AnalyzeMail expected IP ranges %v; got %v", expectedCidrs, res.Cidrs)
+	}
+
+	expectedReports := []MailLead{
+		{Value: "dmarc@example.com", Mechanism: "rua", Origin: "example.com"},
+		{Value: "forensics@reports.example.org", Mechanism: "ruf", Origin: "example.com"},
+	}
+	if !reflect.DeepEqual(res.Reports, expectedReports) {
+		t.Errorf("AnalyzeMail expected report addresses %v; got %v", expectedReports, res.Reports)
+	}
+
+	// the SPF records of both domains, the DMARC record and both DKIM keys
+	var names []string
+	for _, record := range res.Records {
+		names = append(names, record.Name)
+	}
+
+	expectedNames := []string{
+		"example.com.", "_spf.example.net.", "_dmarc.example.com.",
+		"selector1-example-com._domainkey.tenant.onmicrosoft.com.", "google._domainkey.example.com.",
+	}
+	if !reflect.DeepEqual(names, expectedNames) {
+		t.Errorf("AnalyzeMail expected records of %v; got %v", expectedNames, names)
+	}
+}
@@ -0,0 +1,206 @@
+package osint
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Words commonly inserted in or appended to the labels of subdomains
+var PermutationWords = []string{
+	"api", "admin", "app", "beta", "backup", "cdn", "dev", "internal", "new", "old", "portal", "prod", "qa",
+	"stage", "staging", "test", "uat", "v1", "v2", "vpn",
+}
+
+// Environment names swapped with each other (e.g. api-dev -> api-prod)
+var environmentTokens = []string{"dev", "development", "test", "qa", "uat", "stage", "staging", "preprod", "prod", "production"}
+
+var (
+	labelNumberRegex = regexp.MustCompile(`\d+`)
+	labelRegex       = regexp.MustCompile(`^[a-z0-9_]([a-z0-9_-]{0,61}[a-z0-9_])?$`)
+)
+
+// Generates the likely siblings of subdomains of the domain by inserting and appending words, incrementing numbers,
+// swapping environment names and recombining the labels of different subdomains. Names that are already known are
+// not returned.
+func GeneratePermutations(subdomains []string, domain string, words []string) []string {
+	domain = strings.ToLower(strings.TrimSuffix(domain, "."))
+
+	known := map[string]struct{}{}
+	var prefixes [][]string
+	var leftmostLabels []string
+
+	for _, subdomain := range subdomains {
+		name := strings.ToLower(strings.TrimSuffix(subdomain, "."))
+		known[name] = struct{}{}
+
+		if !strings.HasSuffix(name, "."+domain) {
+			continue
+		}
+
+		labels := strings.Split(strings.TrimSuffix(name, "."+domain), ".")
+		prefixes = append(prefixes, labels)
+
+		if !shared.SliceContains(leftmostLabels, labels[0]) {
+			leftmostLabels = append(leftmostLabels, labels[0])
+		}
+	}
+
+	var candidates []string
+	seen := map[string]struct{}{}
+	add := func(labels []string) {
+		name := strings.Join(labels, ".") + "." + domain
+		if _, exists := known[name]; exists {
+			return
+		}
+
+		if _, exists := seen[name]; exists || !validLabels(labels) {
+			return
+		}
+
+		seen[name] = struct{}{}
+		candidates = append(candidates, name)
+	}
+
+	for _, labels := range prefixes {
+		for _, word := range words {
+			// a new label at every position (e.g. dev.api, api.dev)
+			for i := 0; i <= len(labels); i++ {
+				add(insertLabel(labels, i, word))
+			}
+
+			// the word joined to every label (e.g. dev-api, api-dev, apidev)
+			for i, label := range labels {
+				for _, joined := range []string{word + "-" + label, label + "-" + word, word + label, label + word} {
+					add(replaceLabel(labels, i, joined))
+				}
+			}
+		}
+
+		for i, label := range labels {
+			for _, variant := range numberVariants(label) {
+				add(replaceLabel(labels, i, variant))
+			}
+
+			for _, variant := range environmentVariants(label) {
+				add(replaceLabel(labels, i, variant))
+			}
+		}
+
+		// the leftmost label of other subdomains in front of this one's parent (e.g. www.staging from api.staging)
+		if len(labels) > 1 {
+			for _, leftmost := range leftmostLabels {
+				add(replaceLabel(labels, 0, leftmost))
+			}
+		}
+	}
+
+	return candidates
+}
+
+// Resolves the permutations of the known subdomains, and then the permutations of the subdomains found by them,
+// until no new subdomains are found or maxRounds rounds are done. Wildcard answers are filtered out.
+func (resolver *SubdomainResolver) Permute(domain string, known []string, maxRounds int) []ResolvedHost {
+	all := append([]string{}, known...)
+	tried := map[string]struct{}{}
+
+	var found []ResolvedHost
+	for round := 0; round < maxRounds; round++ {
+		var candidates []string
+		for _, candidate := range GeneratePermutations(all, domain, PermutationWords) {
+			if _, exists := tried[candidate]; !exists {
+				tried[candidate] = struct{}{}
+				candidates = append(candidates, candidate)
+			}
+		}
+
+		hosts := resolver.ResolveAll(candidates)
+		if len(hosts) == 0 {
+			break
+		}
+
+		for _, host := range hosts {
+			all = append(all, host.Name)
+		}
+
+		found = append(found, hosts...)
+	}
+
+	return found
+}
+
+func insertLabel(labels []string, i int, label string) []string {
+	inserted := append([]string{}, labels[:i]...)
+	inserted = append(inserted, label)
+	return append(inserted, labels[i:]...)
+}
+
+func replaceLabel(labels []string, i int, label string) []string {
+	replaced := append([]string{}, labels...)
+	replaced[i] = label
+	return replaced
+}
+
+// Returns the label with each of its numbers incremented and decremented (e.g. api2 -> api3, api1), or with a
+// number appended if it has none (e.g. api -> api1, api2)
+func numberVariants(label string) []string {
+	matches := labelNumberRegex.FindAllStringIndex(label, -1)
+	if len(matches) == 0 {
+		return []string{label + "1", label + "2"}
+	}
+
+	var variants []string
+	for _, match := range matches {
+		number, err := strconv.Atoi(label[match[0]:match[1]])
+		if err != nil {
+			continue
+		}
+
+		for _, next := range []int{number + 1, number - 1} {
+			if next < 0 {
+				continue
+			}
+
+			variants = append(variants, label[:match[0]]+strconv.Itoa(next)+label[match[1]:])
+		}
+	}
+
+	return variants
+}
+
+// Returns the label with its environment name swapped for every other one (e.g. api-dev -> api-prod)
+func environmentVariants(label string) []string {
+	parts := strings.Split(label, "-")
+
+	var variants []string
+	for i, part := range parts {
+		if !shared.SliceContains(environmentTokens, part) {
+			continue
+		}
+
+		for _, token := range environmentTokens {
+			if token == part {
+				continue
+			}
+
+			swapped := append([]string{}, parts...)
+			swapped[i] = token
+			variants = append(variants, strings.Join(swapped, "-"))
+		}
+	}
+
+	return variants
+}
+
+// Checks if every label is a valid host name label
+func validLabels(labels []string) bool {
+	for _, label := range labels {
+		if !labelRegex.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
+}
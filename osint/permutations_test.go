@@ -0,0 +1,76 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGeneratePermutations(t *testing.T) {
+	res := GeneratePermutations([]string{"api.example.com", "www.staging.example.com", "other.com"}, "example.com", []string{"dev"})
+
+	expected := []string{
+		// api.example.com
+		"dev.api.example.com", "api.dev.example.com", "dev-api.example.com", "api-dev.example.com",
+		"devapi.example.com", "apidev.example.com", "api1.example.com", "api2.example.com",
+		// www.staging.example.com
+		"dev.www.staging.example.com", "www.dev.staging.example.com", "www.staging.dev.example.com",
+		"dev-www.staging.example.com", "www-dev.staging.example.com", "devwww.staging.example.com",
+		"wwwdev.staging.example.com", "www.dev-staging.example.com", "www.staging-dev.example.com",
+		"www.devstaging.example.com", "www.stagingdev.example.com", "www1.staging.example.com",
+		"www2.staging.example.com", "www.staging1.example.com", "www.staging2.example.com",
+		"www.dev.example.com", "www.development.example.com", "www.test.example.com", "www.qa.example.com", "www.uat.example.com",
+		"www.stage.example.com", "www.preprod.example.com", "www.prod.example.com", "www.production.example.com",
+		"api.staging.example.com",
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("GeneratePermutations expected %v; got %v", expected, res)
+	}
+}
+
+func TestNumberVariants(t *testing.T) {
+	cases := map[string][]string{
+		"api":    {"api1", "api2"},
+		"web0":   {"web1"},
+		"db2-r9": {"db3-r9", "db1-r9", "db2-r10", "db2-r8"},
+	}
+
+	for label, expected := range cases {
+		if res := numberVariants(label); !reflect.DeepEqual(res, expected) {
+			t.Errorf("numberVariants(%s) expected %v; got %v", label, expected, res)
+		}
+	}
+}
+
+func TestPermute(t *testing.T) {
+	addr := startStubResolver(t,
+		"api.example.com. 300 IN A 192.0.2.1",
+		"api-dev.example.com. 300 IN A 192.0.2.2",
+		"api-dev2.example.com. 300 IN A 192.0.2.3",
+		"api-dev3.example.com. 300 IN A 192.0.2.4",
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 4, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// api-dev2 is only generated from api-dev, and api-dev3 from api-dev2
+	res := resolver.Permute("example.com", []string{"api.example.com"}, 2)
+
+	var names []string
+	for _, host := range res {
+		names = append(names, host.Name)
+	}
+
+	expected := []string{"api-dev.example.com", "api-dev2.example.com"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("Permute expected %v; got %v", expected, names)
+	}
+
+	res = resolver.Permute("example.com", []string{"api.example.com"}, 5)
+	if len(res) != 3 {
+		t.Errorf("Permute expected 3 subdomains until no new ones are found; got %v", res)
+	}
+}
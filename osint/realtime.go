@@ -0,0 +1,266 @@
+package osint
+
+import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Kinds of real-time endpoints, named after their JavaScript constructors
+const (
+	WebSocketKind   = "WebSocket"
+	EventSourceKind = "EventSource"
+)
+
+// Time allowed for a handshake to complete
+const handshakeTimeout = 10 * time.Second
+
+// GUID appended to the key when computing Sec-WebSocket-Accept (RFC 6455)
+const websocketGuid = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	websocketUrlRegex = regexp.MustCompile("wss?://[^\\s\"'`<>()\\\\]+")
+
+	// the URL is only captured when the first argument is a string literal
+	realtimeCallRegex = regexp.MustCompile("new\\s+(WebSocket|EventSource)\\s*\\(\\s*(?:[\"'`]([^\"'`]*)[\"'`])?")
+)
+
+// WebSocket or Server-Sent Events endpoint referenced by a page
+type RealtimeEndpoint struct {
+	Kind string
+	Url  string
+}
+
+// Real-time endpoint waiting to be verified, along with the page that referenced it
+type realtimeRef struct {
+	kind     string
+	endpoint url.URL
+	page     url.URL
+}
+
+// Finds ws:// and wss:// URLs, and the URLs passed to WebSocket and EventSource constructors in HTML or
+// JavaScript. Constructor calls with a non-literal argument are returned with an empty URL.
+func ExtractRealtimeEndpoints(code string) []RealtimeEndpoint {
+	seen := map[RealtimeEndpoint]struct{}{}
+
+	var endpoints []RealtimeEndpoint
+	add := func(endpoint RealtimeEndpoint) {
+		if _, exists := seen[endpoint]; exists {
+			return
+		}
+
+		seen[endpoint] = struct{}{}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	for _, match := range realtimeCallRegex.FindAllStringSubmatch(code, -1) {
+		kind := match[1]
+
+		// template literals with placeholders cannot be resolved statically
+		endpoint := match[2]
+		if strings.Contains(endpoint, "${") {
+			endpoint = ""
+		}
+
+		add(RealtimeEndpoint{Kind: kind, Url: endpoint})
+	}
+
+	for _, match := range websocketUrlRegex.FindAllString(code, -1) {
+		if strings.Contains(match, "${") {
+			continue
+		}
+
+		add(RealtimeEndpoint{Kind: WebSocketKind, Url: strings.TrimRight(match, ".,;")})
+	}
+
+	return endpoints
+}
+
+// Computes the expected Sec-WebSocket-Accept value for a Sec-WebSocket-Key
+func websocketAccept(key string) string {
+	hash := sha1.Sum([]byte(key + websocketGuid))
+	return base64.StdEncoding.EncodeToString(hash[:])
+}
+
+// Resolves an endpoint against the page that referenced it. WebSocket endpoints use the ws/wss schemes.
+func resolveRealtimeEndpoint(kind string, ref string, pageUrl url.URL) (url.URL, bool) {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return url.URL{}, false
+	}
+
+	endpoint := *pageUrl.ResolveReference(parsed)
+	endpoint.Fragment = ""
+
+	if kind == WebSocketKind {
+		switch endpoint.Scheme {
+		case "http":
+			endpoint.Scheme = "ws"
+		case "https":
+			endpoint.Scheme = "wss"
+		}
+
+		return endpoint, endpoint.Scheme == "ws" || endpoint.Scheme == "wss"
+	}
+
+	return endpoint, endpoint.Scheme == "http" || endpoint.Scheme == "https"
+}
+
+// Finds the real-time endpoints referenced by the page and queues them to be verified. In-scope scripts loaded by
+// the page are searched as well once the depth is crawled, since bundled JavaScript usually opens the connections.
+func (crawler *Crawler) findRealtimeEndpoints(pg page) {
+	found := ExtractRealtimeEndpoints(string(pg.body))
+
+	for _, ws := range pg.websockets {
+		found = append(found, RealtimeEndpoint{Kind: WebSocketKind, Url: ws})
+	}
+
+	for _, es := range pg.eventSources {
+		found = append(found, RealtimeEndpoint{Kind: EventSourceKind, Url: es})
+	}
+
+	crawler.queueRealtimeEndpoints(found, pg.url, pg.url)
+}
+
+// Queues endpoints to be verified. Endpoints are resolved against the page, since scripts run in its origin, and
+// call sites without a literal URL are reported against the code they were found in.
+func (crawler *Crawler) queueRealtimeEndpoints(found []RealtimeEndpoint, pageUrl url.URL, codeUrl url.URL) {
+	for _, endpoint := range found {
+		if endpoint.Url == "" {
+			crawler.handleHostFinding(shared.Finding{
+				Type:   shared.RealtimeCallSite,
+				Value:  "new " + endpoint.Kind + "(...)",
+				Url:    codeUrl,
+				Source: shared.Realtime,
+			})
+			continue
+		}
+
+		resolved, ok := resolveRealtimeEndpoint(endpoint.Kind, endpoint.Url, pageUrl)
+		if !ok {
+			continue
+		}
+
+		crawler.mutex.Lock()
+		if _, exists := crawler.realtimeMap[resolved.String()]; !exists {
+			crawler.realtimeMap[resolved.String()] = struct{}{}
+			crawler.realtimeQueue = append(crawler.realtimeQueue, realtimeRef{
+				kind:     endpoint.Kind,
+				endpoint: resolved,
+				page:     pageUrl,
+			})
+		}
+		crawler.mutex.Unlock()
+	}
+}
+
+// Verifies every queued endpoint concurrently, respecting the crawler's thread count and delay.
+// Endpoints on out-of-scope hosts are reported without being contacted.
+func (crawler *Crawler) verifyQueuedRealtimeEndpoints() {
+	crawler.mutex.Lock()
+	queue := crawler.realtimeQueue
+	crawler.realtimeQueue = []realtimeRef{}
+	crawler.mutex.Unlock()
+
+	semaphore := make(chan struct{}, crawler.threads)
+	var wg sync.WaitGroup
+
+	for _, ref := range queue {
+		wg.Add(1)
+
+		go func(ref realtimeRef) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			status := "not verified (out of scope)"
+			if shared.SameBaseDomain(ref.endpoint.Hostname(), crawler.seedUrl.Hostname()) {
+				reqTime := time.Now()
+				status = crawler.handshake(ref)
+				crawler.waitDelay(reqTime)
+			}
+
+			findingType := shared.WebSocketEndpoint
+			if ref.kind == EventSourceKind {
+				findingType = shared.EventSourceEndpoint
+			}
+
+			crawler.handleHostFinding(shared.Finding{
+				Type:   findingType,
+				Value:  ref.endpoint.String() + " (" + status + ")",
+				Url:    ref.page,
+				Source: shared.Realtime,
+			})
+		}(ref)
+	}
+
+	wg.Wait()
+}
+
+// Performs a WebSocket opening handshake or an EventSource request, and describes the outcome
+func (crawler *Crawler) handshake(ref realtimeRef) string {
+	target := ref.endpoint
+	switch target.Scheme {
+	case "ws":
+		target.Scheme = "http"
+	case "wss":
+		target.Scheme = "https"
+	}
+
+	req, err := crawler.newRequest(http.MethodGet, target, nil)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+
+	key := ""
+	if ref.kind == WebSocketKind {
+		nonce := make([]byte, 16)
+		rand.Read(nonce)
+		key = base64.StdEncoding.EncodeToString(nonce)
+
+		req.Header.Set("Connection", "Upgrade")
+		req.Header.Set("Upgrade", "websocket")
+		req.Header.Set("Sec-WebSocket-Version", "13")
+		req.Header.Set("Sec-WebSocket-Key", key)
+		req.Header.Set("Origin", ref.page.Scheme+"://"+ref.page.Host)
+	} else {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+
+	client := &http.Client{Timeout: handshakeTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+
+	// the body is either the upgraded connection or an endless stream, so only the headers are read
+	resp.Body.Close()
+
+	if ref.kind == WebSocketKind {
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			return fmt.Sprintf("not upgraded, status %d", resp.StatusCode)
+		}
+
+		if resp.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+			return "upgraded with invalid Sec-WebSocket-Accept"
+		}
+
+		return "upgraded"
+	}
+
+	if resp.StatusCode == http.StatusOK && strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return "streaming"
+	}
+
+	return fmt.Sprintf("not streaming, status %d", resp.StatusCode)
+}
@@ -0,0 +1,95 @@
+package osint
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+func TestExtractRealtimeEndpoints(t *testing.T) {
+	code := "const ws = new WebSocket('wss://live.example.com/socket'); " +
+		"const events = new EventSource(\"/api/events\"); " +
+		"const dynamic = new WebSocket(`wss://${host}/ws`); " +
+		"const other = new WebSocket(socketUrl); " +
+		"var fallback = \"ws://legacy.example.com:8080/feed\";"
+
+	expected := []RealtimeEndpoint{
+		{WebSocketKind, "wss://live.example.com/socket"},
+		{EventSourceKind, "/api/events"},
+		{WebSocketKind, ""},
+		{WebSocketKind, "ws://legacy.example.com:8080/feed"},
+	}
+
+	res := ExtractRealtimeEndpoints(code)
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ExtractRealtimeEndpoints expected %v; got %v", expected, res)
+	}
+}
+
+func TestResolveRealtimeEndpoint(t *testing.T) {
+	pageUrl, _ := url.Parse("https://example.com/dashboard")
+
+	cases := []struct {
+		kind     string
+		ref      string
+		expected string
+		ok       bool
+	}{
+		{WebSocketKind, "/ws", "wss://example.com/ws", true},
+		{WebSocketKind, "ws://example.com:8080/feed", "ws://example.com:8080/feed", true},
+		{EventSourceKind, "/api/events", "https://example.com/api/events", true},
+		{EventSourceKind, "ftp://example.com/events", "", false},
+	}
+
+	for _, tc := range cases {
+		res, ok := resolveRealtimeEndpoint(tc.kind, tc.ref, *pageUrl)
+		if ok != tc.ok || (ok && res.String() != tc.expected) {
+			t.Errorf("resolveRealtimeEndpoint(%s) expected %s, %v; got %s, %v", tc.ref, tc.expected, tc.ok, res.String(), ok)
+		}
+	}
+}
+
+func TestWebsocketAccept(t *testing.T) {
+	// example from RFC 6455, section 1.3
+	if res := websocketAccept("dGhlIHNhbXBsZSBub25jZQ=="); res != "s3pPLMBiTxaQ9kYGzzhZRbK+xOo=" {
+		t.Errorf("websocketAccept expected s3pPLMBiTxaQ9kYGzzhZRbK+xOo=; got %s", res)
+	}
+}
+
+func TestRealtimeEndpointsInScripts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			w.Write([]byte(`<html><body><script src="/static/app.js"></script></body></html>`))
+		case "/static/app.js":
+			w.Write([]byte(`const socket = new WebSocket("/live"); const feed = new EventSource(feedUrl);`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	seed, _ := url.Parse(server.URL)
+	comms := shared.NewCommsChannels()
+
+	crawler := NewCrawler(false, true, *seed, 2, 0, []url.URL{*seed}, 1, comms, map[string]string{}, map[string]string{})
+	crawler.EnableRealtimeDiscovery()
+
+	var values []string
+	for _, finding := range collectCrawlFindings(&crawler, comms) {
+		values = append(values, string(finding.Type)+" "+finding.Value)
+	}
+
+	expected := []string{
+		string(shared.RealtimeCallSite) + " new EventSource(...)",
+		string(shared.WebSocketEndpoint) + " ws://" + seed.Host + "/live (not upgraded, status 404)",
+	}
+
+	if !reflect.DeepEqual(values, expected) {
+		t.Errorf("Crawl expected the endpoints of the external script %v; got %v", expected, values)
+	}
+}
@@ -0,0 +1,303 @@
+package osint
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Errors
+const (
+	invalidResolverErr = "invalid DNS resolver (expected host[:port], tcp://, tls:// or https:// resolver)"
+	dohStatusErr       = "DNS-over-HTTPS resolver answered with unexpected status"
+)
+
+// Amount of consecutive failures after which a resolver is skipped, and for how long it is skipped
+const (
+	maxResolverFailures = 3
+	unhealthyCooldown   = 30 * time.Second
+)
+
+// Bounds of how long answers are cached, and of how many answers are kept
+const (
+	maxCacheTtl     = 5 * time.Minute
+	maxCacheEntries = 10000
+)
+
+// Maximum size of a DNS message, which bounds DNS-over-HTTPS response bodies
+const maxDnsMessageSize = 65535
+
+// Media type of DNS messages sent over HTTPS (RFC 8484)
+const dnsMessageType = "application/dns-message"
+
+// Resolver that DNS messages are sent to over a given transport
+type Upstream interface {
+	Exchange(msg *dns.Msg) (*dns.Msg, error)
+	String() string
+}
+
+// Resolver queried over UDP, with truncated answers requested again over TCP, or over TCP only
+type plainUpstream struct {
+	address string
+	tcpOnly bool
+	udp     *dns.Client
+	tcp     *dns.Client
+}
+
+// Resolver queried over TLS (RFC 7858)
+type tlsUpstream struct {
+	address string
+	client  *dns.Client
+}
+
+// Resolver queried over HTTPS (RFC 8484)
+type httpsUpstream struct {
+	endpoint string
+	client   *http.Client
+}
+
+// Resolver along with its rate limit and health
+type upstreamState struct {
+	upstream Upstream
+	interval time.Duration
+
+	mutex          sync.Mutex
+	nextSlot       time.Time
+	failures       int
+	unhealthyUntil time.Time
+}
+
+// Answer kept until its records expire
+type cachedAnswer struct {
+	msg     *dns.Msg
+	server  string
+	expires time.Time
+}
+
+// Parses a resolver into the transport it is queried over:
+//   - 1.1.1.1, 9.9.9.9:5353 or udp://1.1.1.1 for UDP, falling back to TCP for truncated answers
+//   - tcp://1.1.1.1 for TCP only
+//   - tls://1.1.1.1 or tls://dns.example.com:853 for DNS-over-TLS
+//   - https://cloudflare-dns.com/dns-query for DNS-over-HTTPS
+func ParseUpstream(resolver string, timeout time.Duration) (Upstream, error) {
+	resolver = strings.TrimSpace(resolver)
+	if resolver == "" {
+		return nil, fmt.Errorf(invalidResolverErr)
+	}
+
+	scheme, address, found := strings.Cut(resolver, "://")
+	if !found {
+		scheme, address = "udp", resolver
+	}
+
+	switch strings.ToLower(scheme) {
+	case "udp", "tcp":
+		return plainUpstream{
+			address: withDefaultPort(address, "53"),
+			tcpOnly: strings.EqualFold(scheme, "tcp"),
+			udp:     &dns.Client{Timeout: timeout},
+			tcp:     &dns.Client{Net: "tcp", Timeout: timeout},
+		}, nil
+	case "tls":
+		address = withDefaultPort(address, "853")
+		host, _, _ := net.SplitHostPort(address)
+
+		return tlsUpstream{
+			address: address,
+			client:  &dns.Client{Net: "tcp-tls", Timeout: timeout, TLSConfig: &tls.Config{ServerName: host}},
+		}, nil
+	case "https":
+		if u, err := url.Parse(resolver); err != nil || u.Host == "" {
+			return nil, fmt.Errorf(invalidResolverErr)
+		}
+
+		return httpsUpstream{endpoint: resolver, client: &http.Client{Timeout: timeout}}, nil
+	}
+
+	return nil, fmt.Errorf(invalidResolverErr)
+}
+
+// Adds the port to addresses without one (e.g. "1.1.1.1" -> "1.1.1.1:53", "2606:4700::1111" -> "[2606:4700::1111]:53")
+func withDefaultPort(address string, port string) string {
+	if _, _, err := net.SplitHostPort(address); err == nil {
+		return address
+	}
+
+	return net.JoinHostPort(strings.Trim(address, "[]"), port)
+}
+
+func (upstream plainUpstream) Exchange(msg *dns.Msg) (*dns.Msg, error) {
+	if !upstream.tcpOnly {
+		res, _, err := upstream.udp.Exchange(msg, upstream.address)
+		if err != nil || !res.Truncated {
+			return res, err
+		}
+	}
+
+	res, _, err := upstream.tcp.Exchange(msg, upstream.address)
+	return res, err
+}
+
+func (upstream plainUpstream) String() string {
+	if upstream.tcpOnly {
+		return "tcp://" + upstream.address
+	}
+
+	return upstream.address
+}
+
+func (upstream tlsUpstream) Exchange(msg *dns.Msg) (*dns.Msg, error) {
+	res, _, err := upstream.client.Exchange(msg, upstream.address)
+	return res, err
+}
+
+func (upstream tlsUpstream) String() string {
+	return "tls://" + upstream.address
+}
+
+// Sends the message in the body of a POST request. The message ID is sent as 0 so answers can be cached by HTTP
+// caches, as RFC 8484 recommends, and restored in the answer.
+func (upstream httpsUpstream) Exchange(msg *dns.Msg) (*dns.Msg, error) {
+	query := msg.Copy()
+	query.Id = 0
+
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, upstream.endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", dnsMessageType)
+	req.Header.Set("Accept", dnsMessageType)
+
+	resp, err := upstream.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf(dohStatusErr)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDnsMessageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	res := new(dns.Msg)
+	if err := res.Unpack(body); err != nil {
+		return nil, err
+	}
+	res.Id = msg.Id
+
+	return res, nil
+}
+
+func (upstream httpsUpstream) String() string {
+	return upstream.endpoint
+}
+
+// Creates the state of a resolver that sends at most rate queries per second (no limit if 0)
+func newUpstreamState(upstream Upstream, rate int) *upstreamState {
+	state := &upstreamState{upstream: upstream}
+	if rate > 0 {
+		state.interval = time.Second / time.Duration(rate)
+	}
+
+	return state
+}
+
+// Blocks until the resolver's rate limit allows another query
+func (state *upstreamState) wait() {
+	if state.interval == 0 {
+		return
+	}
+
+	state.mutex.Lock()
+	now := time.Now()
+	if state.nextSlot.Before(now) {
+		state.nextSlot = now
+	}
+
+	slot := state.nextSlot
+	state.nextSlot = slot.Add(state.interval)
+	state.mutex.Unlock()
+
+	time.Sleep(time.Until(slot))
+}
+
+func (state *upstreamState) healthy() bool {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	return time.Now().After(state.unhealthyUntil)
+}
+
+// Records the outcome of a query, skipping the resolver for a while after too many consecutive failures
+func (state *upstreamState) record(err error) {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	if err == nil {
+		state.failures = 0
+		return
+	}
+
+	state.failures++
+	if state.failures >= maxResolverFailures {
+		state.failures = 0
+		state.unhealthyUntil = time.Now().Add(unhealthyCooldown)
+	}
+}
+
+func (state *upstreamState) markUnhealthy() {
+	state.mutex.Lock()
+	defer state.mutex.Unlock()
+
+	state.failures = 0
+	state.unhealthyUntil = time.Now().Add(unhealthyCooldown)
+}
+
+// Returns the key an answer to the message is cached under, or false if the message cannot be cached
+func cacheKey(msg *dns.Msg) (string, bool) {
+	if len(msg.Question) != 1 {
+		return "", false
+	}
+
+	question := msg.Question[0]
+	dnssec := false
+	if opt := msg.IsEdns0(); opt != nil {
+		dnssec = opt.Do()
+	}
+
+	return fmt.Sprintf("%s %d %d %t", strings.ToLower(question.Name), question.Qtype, question.Qclass, dnssec), true
+}
+
+// Returns how long an answer can be cached for: the lowest TTL of its records, up to maxCacheTtl. Answers without
+// records (e.g. NXDOMAIN) are not cached, since most of them answer brute forced or random names that are only
+// queried once.
+func cacheTtl(res *dns.Msg) time.Duration {
+	if len(res.Answer) == 0 {
+		return 0
+	}
+
+	ttl := maxCacheTtl
+	for _, rr := range append(append([]dns.RR{}, res.Answer...), res.Ns...) {
+		ttl = min(ttl, time.Duration(rr.Header().Ttl)*time.Second)
+	}
+
+	return ttl
+}
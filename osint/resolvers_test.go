@@ -0,0 +1,241 @@
+package osint
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Answers www.example.com with an address and any other name with NXDOMAIN
+func answerStubQuery(req *dns.Msg) *dns.Msg {
+	res := new(dns.Msg)
+	res.SetReply(req)
+
+	if req.Question[0].Name != "www.example.com." {
+		res.Rcode = dns.RcodeNameError
+		return res
+	}
+
+	rr, _ := dns.NewRR("www.example.com. 300 IN A 192.0.2.1")
+	res.Answer = append(res.Answer, rr)
+
+	return res
+}
+
+// Starts a DNS server on the listener, returning its address
+func serveDnsListener(t *testing.T, listener net.Listener, handler dns.Handler) string {
+	server := &dns.Server{Listener: listener, Handler: handler}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	return listener.Addr().String()
+}
+
+func TestUpstreamTransports(t *testing.T) {
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		w.WriteMsg(answerStubQuery(req))
+	})
+
+	// the HTTPS server's certificate is reused by the DNS-over-TLS server
+	doh := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != dnsMessageType {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		req := new(dns.Msg)
+		if err := req.Unpack(body); err != nil || req.Id != 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		packed, _ := answerStubQuery(req).Pack()
+		w.Header().Set("Content-Type", dnsMessageType)
+		w.Write(packed)
+	}))
+	t.Cleanup(doh.Close)
+
+	roots := doh.Client().Transport.(*http.Transport).TLSClientConfig.RootCAs
+
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tlsListener, err := tls.Listen("tcp", "127.0.0.1:0", &tls.Config{Certificates: doh.TLS.Certificates})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	udp, _ := ParseUpstream(serveDns(t, handler), time.Second)
+	tcp, _ := ParseUpstream("tcp://"+serveDnsListener(t, tcpListener, handler), time.Second)
+
+	dot, _ := ParseUpstream("tls://"+serveDnsListener(t, tlsListener, handler), time.Second)
+	dot.(tlsUpstream).client.TLSConfig.RootCAs = roots
+
+	https, _ := ParseUpstream(doh.URL+"/dns-query", time.Second)
+	https = httpsUpstream{endpoint: https.String(), client: doh.Client()}
+
+	for _, upstream := range []Upstream{udp, tcp, dot, https} {
+		msg := new(dns.Msg)
+		msg.SetQuestion("www.example.com.", dns.TypeA)
+
+		res, err := upstream.Exchange(msg)
+		if err != nil {
+			t.Errorf("Exchange expected answer from %s; got %v", upstream, err)
+			continue
+		}
+
+		if res.Id != msg.Id || len(res.Answer) != 1 || res.Answer[0].(*dns.A).A.String() != "192.0.2.1" {
+			t.Errorf("Exchange expected 192.0.2.1 from %s; got %v", upstream, res)
+		}
+	}
+}
+
+func TestResolverHealth(t *testing.T) {
+	// nothing listens on the address once the connection is closed, so queries are refused
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dead := conn.LocalAddr().String()
+	conn.Close()
+
+	live := startStubResolver(t, "www.example.com. 300 IN A 192.0.2.1")
+
+	resolver, err := NewSubdomainResolver([]string{dead, live}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res := resolver.CheckHealth(); len(res) != 1 || res[0] != dead {
+		t.Errorf("CheckHealth expected %s to fail; got %v", dead, res)
+	}
+
+	// unhealthy resolvers are skipped by the rotation
+	for _, name := range []string{"a.example.com", "b.example.com", "c.example.com"} {
+		if _, server, err := resolver.queryWithServer(name, dns.TypeA); err != nil || server != live {
+			t.Errorf("queryWithServer expected answer from %s; got %s (%v)", live, server, err)
+		}
+	}
+
+	// resolvers that fail repeatedly are skipped without a health check
+	resolver, _ = NewSubdomainResolver([]string{dead, live}, 0, 1, time.Second)
+	for _, name := range []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com", "e.example.com", "f.example.com"} {
+		if _, err := resolver.query(name, dns.TypeA); err != nil {
+			t.Errorf("query expected failed attempts to be retried with another resolver; got %v", err)
+		}
+	}
+
+	if resolver.upstreams[0].healthy() || !resolver.upstreams[1].healthy() {
+		t.Errorf("query expected only %s to be marked unhealthy", dead)
+	}
+}
+
+func TestResolverRateLimit(t *testing.T) {
+	addr := startStubResolver(t, "www.example.com. 300 IN A 192.0.2.1")
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 20, 4, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := []string{"a.example.com", "b.example.com", "c.example.com", "d.example.com", "e.example.com"}
+
+	start := time.Now()
+	resolver.ResolveAll(names)
+
+	// the names do not exist, so a single query is sent for each, 50ms apart
+	if elapsed := time.Since(start); elapsed < 190*time.Millisecond {
+		t.Errorf("ResolveAll expected at most 20 queries per second; took %v", elapsed)
+	}
+}
+
+func TestResolverCache(t *testing.T) {
+	var queries int32
+	addr := serveDns(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		atomic.AddInt32(&queries, 1)
+		w.WriteMsg(answerStubQuery(req))
+	}))
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		msg := new(dns.Msg)
+		msg.SetQuestion("www.example.com.", dns.TypeA)
+
+		res, server, err := resolver.exchange(msg)
+		if err != nil || res.Id != msg.Id || len(res.Answer) != 1 || server != addr {
+			t.Errorf("exchange expected the cached answer with the query's ID; got %v from %s (%v)", res, server, err)
+		}
+	}
+
+	// answers to DNSSEC queries are cached separately, since they carry more records
+	if _, err := resolver.queryDnssec("www.example.com", dns.TypeA); err != nil {
+		t.Fatal(err)
+	}
+
+	// names that do not exist are asked again, since most are only queried once
+	for i := 0; i < 2; i++ {
+		if _, err := resolver.query("missing.example.com", dns.TypeA); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if count := atomic.LoadInt32(&queries); count != 4 {
+		t.Errorf("exchange expected 4 queries to reach the resolver; got %d", count)
+	}
+}
+
+func TestResolverCacheLimit(t *testing.T) {
+	resolver, err := NewSubdomainResolver([]string{"127.0.0.1"}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := func(name string, ttl uint32) {
+		msg := new(dns.Msg)
+		msg.SetQuestion(name, dns.TypeA)
+
+		rr, _ := dns.NewRR(fmt.Sprintf("%s %d IN A 192.0.2.1", name, ttl))
+		res := new(dns.Msg)
+		res.SetReply(msg)
+		res.Answer = append(res.Answer, rr)
+
+		key, _ := cacheKey(msg)
+		resolver.store(key, res, "127.0.0.1:53")
+	}
+
+	// the answer closest to expiring is dropped first
+	store("short.example.com.", 10)
+	for i := 1; i < maxCacheEntries; i++ {
+		store(fmt.Sprintf("host%d.example.com.", i), 300)
+	}
+	store("new.example.com.", 300)
+
+	if len(resolver.cache) != maxCacheEntries {
+		t.Errorf("store expected at most %d cached answers; got %d", maxCacheEntries, len(resolver.cache))
+	}
+
+	short, _ := cacheKey(new(dns.Msg).SetQuestion("short.example.com.", dns.TypeA))
+	if _, _, found := resolver.cached(short, 1); found {
+		t.Errorf("store expected the answer closest to expiring to be evicted")
+	}
+
+	added, _ := cacheKey(new(dns.Msg).SetQuestion("new.example.com.", dns.TypeA))
+	if _, _, found := resolver.cached(added, 1); !found {
+		t.Errorf("store expected the new answer to be cached")
+	}
+}
@@ -0,0 +1,155 @@
+package osint
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	invalidCidrErr  = "invalid IP address or CIDR"
+	cidrTooLargeErr = "CIDR is larger than the maximum sweep size"
+)
+
+// Maximum amount of host bits of a CIDR that is expanded, so a single range is at most the size of an IPv4 /16
+const maxSweepHostBits = 16
+
+// Host names an IP address points back to through its PTR records
+type PtrRecord struct {
+	IP    string
+	Hosts []string
+}
+
+// Returns the addresses to sweep: every IP, the /24 of every IPv4 address if expand is set, and every address of
+// the CIDRs. Addresses are returned once, in the order they were first seen.
+func SweepTargets(ips []string, cidrs []string, expand bool) ([]string, error) {
+	var targets []string
+	seen := map[string]struct{}{}
+	add := func(addresses []string) {
+		for _, address := range addresses {
+			if _, exists := seen[address]; !exists {
+				seen[address] = struct{}{}
+				targets = append(targets, address)
+			}
+		}
+	}
+
+	for _, ip := range ips {
+		parsed := net.ParseIP(ip)
+		if parsed == nil {
+			continue
+		}
+
+		if ip4 := parsed.To4(); ip4 != nil && expand {
+			network := net.IPNet{IP: ip4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}
+			addresses, _ := ExpandCidr(network.String())
+			add(addresses)
+			continue
+		}
+
+		add([]string{parsed.String()})
+	}
+
+	for _, cidr := range cidrs {
+		addresses, err := ExpandCidr(cidr)
+		if err != nil {
+			return targets, fmt.Errorf("%s: %s", cidr, err)
+		}
+
+		add(addresses)
+	}
+
+	return targets, nil
+}
+
+// Returns every address of the CIDR, or the address itself if it is not a range
+func ExpandCidr(cidr string) ([]string, error) {
+	cidr = strings.TrimSpace(cidr)
+	if ip := net.ParseIP(cidr); ip != nil {
+		return []string{ip.String()}, nil
+	}
+
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return []string{}, fmt.Errorf(invalidCidrErr)
+	}
+
+	ones, bits := network.Mask.Size()
+	if bits-ones > maxSweepHostBits {
+		return []string{}, fmt.Errorf(cidrTooLargeErr)
+	}
+
+	start := new(big.Int).SetBytes(network.IP)
+	count := 1 << (bits - ones)
+
+	addresses := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		n := new(big.Int).Add(start, big.NewInt(int64(i))).Bytes()
+
+		// the integer drops leading zero bytes, which the address needs back
+		ip := make(net.IP, len(network.IP))
+		copy(ip[len(ip)-len(n):], n)
+
+		addresses = append(addresses, ip.String())
+	}
+
+	return addresses, nil
+}
+
+// Looks up the PTR records of every address concurrently, returning the addresses that point to a host name in the
+// order they were given
+func (resolver *SubdomainResolver) ReverseLookup(ips []string) []PtrRecord {
+	results := make([]PtrRecord, len(ips))
+
+	resolver.runWorkers(len(ips), func(i int) {
+		results[i] = resolver.lookupPtr(ips[i])
+	})
+
+	var records []PtrRecord
+	for _, result := range results {
+		if len(result.Hosts) > 0 {
+			records = append(records, result)
+		}
+	}
+
+	return records
+}
+
+func (resolver *SubdomainResolver) lookupPtr(ip string) PtrRecord {
+	record := PtrRecord{IP: ip}
+
+	name, err := dns.ReverseAddr(ip)
+	if err != nil {
+		return record
+	}
+
+	res, err := resolver.query(name, dns.TypePTR)
+	if err != nil {
+		return record
+	}
+
+	for _, rr := range res.Answer {
+		ptr, ok := rr.(*dns.PTR)
+		if !ok {
+			continue
+		}
+
+		host := strings.ToLower(strings.TrimSuffix(ptr.Ptr, "."))
+		if host != "" && !shared.SliceContains(record.Hosts, host) {
+			record.Hosts = append(record.Hosts, host)
+		}
+	}
+
+	return record
+}
+
+// Formats the record as "192.0.2.1 -> host.example.com, other.example.com"
+func (record PtrRecord) String() string {
+	return record.IP + " -> " + strings.Join(record.Hosts, ", ")
+}
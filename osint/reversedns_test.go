@@ -0,0 +1,75 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExpandCidr(t *testing.T) {
+	testCases := []struct {
+		cidr     string
+		expected []string
+		err      bool
+	}{
+		{cidr: "192.0.2.4/30", expected: []string{"192.0.2.4", "192.0.2.5", "192.0.2.6", "192.0.2.7"}},
+		{cidr: "192.0.2.9/31", expected: []string{"192.0.2.8", "192.0.2.9"}},
+		{cidr: "2001:db8::/127", expected: []string{"2001:db8::", "2001:db8::1"}},
+		{cidr: "198.51.100.7", expected: []string{"198.51.100.7"}},
+		{cidr: "10.0.0.0/8", expected: []string{}, err: true},
+		{cidr: "example.com", expected: []string{}, err: true},
+	}
+
+	for _, tc := range testCases {
+		res, err := ExpandCidr(tc.cidr)
+		if (err != nil) != tc.err || !reflect.DeepEqual(res, tc.expected) {
+			t.Errorf("ExpandCidr expected %v (error: %v) for %s; got %v (%v)", tc.expected, tc.err, tc.cidr, res, err)
+		}
+	}
+}
+
+func TestSweepTargets(t *testing.T) {
+	res, err := SweepTargets([]string{"192.0.2.1", "2001:db8::1", "192.0.2.1", "invalid"}, []string{"198.51.100.0/31"}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"192.0.2.1", "2001:db8::1", "198.51.100.0", "198.51.100.1"}
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("SweepTargets expected %v; got %v", expected, res)
+	}
+
+	// both addresses share the same /24, and IPv6 addresses are not expanded
+	res, err = SweepTargets([]string{"192.0.2.1", "192.0.2.200", "2001:db8::1"}, []string{}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 257 || res[0] != "192.0.2.0" || res[255] != "192.0.2.255" || res[256] != "2001:db8::1" {
+		t.Errorf("SweepTargets expected the /24 of 192.0.2.1 and 2001:db8::1; got %d addresses", len(res))
+	}
+}
+
+func TestReverseLookup(t *testing.T) {
+	addr := startStubResolver(t,
+		"1.2.0.192.in-addr.arpa. 300 IN PTR www.example.com.",
+		"1.2.0.192.in-addr.arpa. 300 IN PTR web01.example.com.",
+		"1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa. 300 IN PTR mail.example.net.",
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 2, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := resolver.ReverseLookup([]string{"192.0.2.1", "192.0.2.2", "2001:db8::1"})
+
+	expected := []PtrRecord{
+		{IP: "192.0.2.1", Hosts: []string{"www.example.com", "web01.example.com"}},
+		{IP: "2001:db8::1", Hosts: []string{"mail.example.net"}},
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ReverseLookup expected %v; got %v", expected, res)
+	}
+}
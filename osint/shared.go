@@ -1,8 +1,10 @@
 package osint
 
 import (
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 const CHROME_USER_AGENT = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/123.0.0.0 Safari/537.36"
@@ -25,8 +27,8 @@ func parsePath(urlStr string, host string, scheme string) (url.URL, error) {
 	return *parsedUrl, nil
 }
 
-func generateRequest(url url.URL) (*http.Request, error) {
-	req, err := http.NewRequest(http.MethodGet, url.String(), nil)
+func generateRequest(method string, url url.URL, body io.Reader) (*http.Request, error) {
+	req, err := http.NewRequest(method, url.String(), body)
 	if err != nil {
 		return &http.Request{}, err
 	}
@@ -35,3 +37,22 @@ func generateRequest(url url.URL) (*http.Request, error) {
 
 	return req, nil
 }
+
+// Returns the directories leading to a path, starting from the root (e.g. /, /a/ and /a/b/ for /a/b/c.php).
+// The last segment is only treated as a directory if the path ends with a slash.
+func ParentDirs(path string) []string {
+	dirs := []string{"/"}
+
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	current := "/"
+	for _, segment := range segments[:len(segments)-1] {
+		if segment == "" {
+			continue
+		}
+
+		current += segment + "/"
+		dirs = append(dirs, current)
+	}
+
+	return dirs
+}
@@ -2,6 +2,7 @@ package osint
 
 import (
 	"net/url"
+	"reflect"
 	"testing"
 )
 
@@ -51,3 +52,24 @@ func TestParsePath(t *testing.T) {
 		}
 	}
 }
+
+func TestParentDirs(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected []string
+	}{
+		{"", []string{"/"}},
+		{"/", []string{"/"}},
+		{"/index.php", []string{"/"}},
+		{"/a/b/c.php", []string{"/", "/a/", "/a/b/"}},
+		{"/a/b/", []string{"/", "/a/", "/a/b/"}},
+		{"/a//b", []string{"/", "/a/"}},
+	}
+
+	for _, c := range cases {
+		res := ParentDirs(c.path)
+		if !reflect.DeepEqual(res, c.expected) {
+			t.Errorf("ParentDirs(%q) expected %v; got %v", c.path, c.expected, res)
+		}
+	}
+}
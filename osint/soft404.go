@@ -0,0 +1,227 @@
+package osint
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"hash/fnv"
+	"math/bits"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Size of the buckets response lengths are grouped in
+const lengthBucketSize = 256
+
+// Maximum amount of differing bits between the simhashes of two similar pages
+const maxSimhashDistance = 6
+
+// Suffixes of the random paths requested in every directory, since servers often handle each kind differently
+var soft404Suffixes = []string{"", ".php", ".html", "/"}
+
+var titleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// Describes a response well enough to recognize other responses generated by the same catch-all route
+type PageFingerprint struct {
+	Status       int
+	LengthBucket int
+	Title        string
+	Simhash      uint64
+}
+
+// Random-path fingerprints of a directory, computed the first time they are needed
+type soft404Calibration struct {
+	once         sync.Once
+	fingerprints []PageFingerprint
+}
+
+// Fingerprints a response by its status code, length bucket, title and simhash. Occurrences of the requested
+// path are removed first, since catch-all pages often reflect it.
+func NewPageFingerprint(status int, body []byte, requestPath string) PageFingerprint {
+	content := string(body)
+	if len(requestPath) > 1 {
+		content = strings.ReplaceAll(content, requestPath, "")
+	}
+
+	title := ""
+	if match := titleRegex.FindStringSubmatch(content); match != nil {
+		title = strings.Join(strings.Fields(match[1]), " ")
+	}
+
+	return PageFingerprint{
+		Status:       status,
+		LengthBucket: len(content) / lengthBucketSize,
+		Title:        title,
+		Simhash:      Simhash(content),
+	}
+}
+
+// Checks if two responses were likely generated by the same route. The status codes and titles must be equal,
+// the lengths must fall in the same or adjacent buckets, and the simhashes must be close.
+func (fp PageFingerprint) Matches(other PageFingerprint) bool {
+	if fp.Status != other.Status || fp.Title != other.Title {
+		return false
+	}
+
+	bucketDiff := fp.LengthBucket - other.LengthBucket
+	if bucketDiff < -1 || bucketDiff > 1 {
+		return false
+	}
+
+	return bits.OnesCount64(fp.Simhash^other.Simhash) <= maxSimhashDistance
+}
+
+// Computes the 64-bit simhash of the words of a text, so similar texts have hashes with few differing bits
+func Simhash(text string) uint64 {
+	var weights [64]int
+	for _, word := range strings.Fields(strings.ToLower(text)) {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+
+		for i := 0; i < 64; i++ {
+			if sum&(1<<i) != 0 {
+				weights[i]++
+			} else {
+				weights[i]--
+			}
+		}
+	}
+
+	var hash uint64
+	for i, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << i
+		}
+	}
+
+	return hash
+}
+
+// Returns the directory a page belongs to (e.g. /a/ for both /a/b.php and /a/b/)
+func soft404Dir(pagePath string) string {
+	dir := path.Dir(strings.TrimSuffix(pagePath, "/"))
+	if dir == "/" || dir == "." {
+		return "/"
+	}
+
+	return dir + "/"
+}
+
+// Checks if a response to the URL looks like the responses to random paths in its directory or the host's root.
+// Error responses, the root itself and out-of-scope hosts are never considered soft-404s.
+func (crawler *Crawler) isSoft404(target url.URL, status int, body []byte) bool {
+	if status >= 400 || target.Path == "" || target.Path == "/" {
+		return false
+	}
+
+	if !shared.SameBaseDomain(target.Hostname(), crawler.seedUrl.Hostname()) {
+		return false
+	}
+
+	fp := NewPageFingerprint(status, body, target.Path)
+
+	dirs := []string{soft404Dir(target.Path)}
+	if dirs[0] != "/" {
+		dirs = append(dirs, "/")
+	}
+
+	for _, dirPath := range dirs {
+		if crawler.matchesRandomPaths(url.URL{Scheme: target.Scheme, Host: target.Host, Path: dirPath}, fp) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the fingerprints of random paths in the directory, requesting them the first time the directory is seen.
+// Redirects are fingerprinted both as they are answered and once followed, since content discovery does not follow
+// them but the crawler does.
+func (crawler *Crawler) dirFingerprints(dir url.URL) []PageFingerprint {
+	crawler.mutex.Lock()
+	calibration, exists := crawler.soft404Map[dir.String()]
+	if !exists {
+		calibration = &soft404Calibration{}
+		crawler.soft404Map[dir.String()] = calibration
+	}
+	crawler.mutex.Unlock()
+
+	calibration.once.Do(func() {
+		for _, suffix := range crawler.calibrationSuffixes() {
+			target := resolveDirFile(dir, randomPath()+suffix)
+
+			status, header, body, err := crawler.probeRequestWith(noRedirectClient, http.MethodGet, target, "", nil)
+			if err != nil {
+				continue
+			}
+			calibration.fingerprints = append(calibration.fingerprints, NewPageFingerprint(status, body, target.Path))
+
+			if status < 300 || status >= 400 || header.Get("Location") == "" {
+				continue
+			}
+
+			status, _, body, err = crawler.probeRequest(http.MethodGet, target, "", nil)
+			if err == nil {
+				calibration.fingerprints = append(calibration.fingerprints, NewPageFingerprint(status, body, target.Path))
+			}
+		}
+	})
+
+	return calibration.fingerprints
+}
+
+// Returns the suffixes of the random paths requested in every directory, including the content discovery
+// extensions so catch-all routes for them are recognized
+func (crawler *Crawler) calibrationSuffixes() []string {
+	suffixes := soft404Suffixes
+	if crawler.contentDiscovery == nil {
+		return suffixes
+	}
+
+	for _, ext := range crawler.contentDiscovery.extensions {
+		if !shared.SliceContains(suffixes, "."+ext) {
+			suffixes = append(suffixes[:len(suffixes):len(suffixes)], "."+ext)
+		}
+	}
+
+	return suffixes
+}
+
+// Checks if a response looks like one of the responses to random paths in the directory
+func (crawler *Crawler) matchesRandomPaths(dir url.URL, fp PageFingerprint) bool {
+	for _, baseline := range crawler.dirFingerprints(dir) {
+		if fp.Matches(baseline) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func randomPath() string {
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Reports the page as a probable soft-404 if it looks like the responses to random paths
+func (crawler *Crawler) checkSoft404(pg page) bool {
+	if !crawler.isSoft404(pg.url, pg.statusCode, pg.body) {
+		return false
+	}
+
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.ProbableSoft404,
+		Value:  pg.url.String(),
+		Url:    pg.url,
+		Source: shared.Soft404,
+	})
+
+	return true
+}
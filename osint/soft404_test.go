@@ -0,0 +1,81 @@
+package osint
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+	"testing"
+)
+
+// Generates a catch-all page that reflects the requested path
+func catchAllPage(path string) []byte {
+	return []byte(fmt.Sprintf(`<html><head><title> Example
+		Store </title></head><body><nav>Home Products About Contact Cart</nav>
+		<p>Sorry, we could not find %s. Try searching for products or browse our categories below.</p>
+		<ul><li>Shoes</li><li>Shirts</li><li>Hats</li><li>Bags</li><li>Jackets</li></ul>
+		<footer>Copyright Example Store. All rights reserved.</footer></body></html>`, path))
+}
+
+func TestNewPageFingerprint(t *testing.T) {
+	fp := NewPageFingerprint(200, catchAllPage("/missing"), "/missing")
+
+	if fp.Title != "Example Store" {
+		t.Errorf("NewPageFingerprint expected title %q; got %q", "Example Store", fp.Title)
+	}
+
+	reflected := NewPageFingerprint(200, catchAllPage("/a8f3c1e0"), "/a8f3c1e0")
+	if fp != reflected {
+		t.Errorf("NewPageFingerprint expected reflected paths to be ignored; got %v and %v", fp, reflected)
+	}
+}
+
+func TestSimhash(t *testing.T) {
+	a := Simhash(string(catchAllPage("/a8f3c1e0")))
+	b := Simhash(string(catchAllPage("/admin/settings.php")))
+	if distance := bits.OnesCount64(a ^ b); distance > maxSimhashDistance {
+		t.Errorf("Simhash expected similar pages to differ by at most %d bits; got %d", maxSimhashDistance, distance)
+	}
+
+	c := Simhash(strings.Repeat("completely different content about an unrelated topic ", 5))
+	if distance := bits.OnesCount64(a ^ c); distance <= maxSimhashDistance {
+		t.Errorf("Simhash expected different pages to differ by more than %d bits; got %d", maxSimhashDistance, distance)
+	}
+}
+
+func TestPageFingerprintMatches(t *testing.T) {
+	baseline := NewPageFingerprint(200, catchAllPage("/a8f3c1e0"), "/a8f3c1e0")
+
+	cases := []struct {
+		name     string
+		fp       PageFingerprint
+		expected bool
+	}{
+		{"reflected path", NewPageFingerprint(200, catchAllPage("/admin/settings.php"), "/admin/settings.php"), true},
+		{"different status", NewPageFingerprint(404, catchAllPage("/admin/settings.php"), "/admin/settings.php"), false},
+		{"real page", NewPageFingerprint(200, []byte("<html><head><title>Admin</title></head><body>Settings</body></html>"), "/admin"), false},
+		{"longer page", NewPageFingerprint(200, append(catchAllPage("/x"), []byte(strings.Repeat("<p>more</p>", 100))...), "/x"), false},
+	}
+
+	for _, c := range cases {
+		res := c.fp.Matches(baseline)
+		if res != c.expected {
+			t.Errorf("Matches (%s) expected %v; got %v", c.name, c.expected, res)
+		}
+	}
+}
+
+func TestSoft404Dir(t *testing.T) {
+	cases := map[string]string{
+		"/index.php":    "/",
+		"/a/":           "/",
+		"/a/b.php":      "/a/",
+		"/a/b/":         "/a/",
+		"/a/b/c/d.html": "/a/b/c/",
+	}
+
+	for input, expected := range cases {
+		if res := soft404Dir(input); res != expected {
+			t.Errorf("soft404Dir(%q) expected %q; got %q", input, expected, res)
+		}
+	}
+}
@@ -0,0 +1,398 @@
+package osint
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/caio-ishikawa/netscout/shared"
+	"golang.org/x/net/html"
+)
+
+// Errors
+const (
+	unsupportedSourceMapErr = "source map is not a version 3 source map"
+	invalidDataUriErr       = "source map data URI is not base64 encoded"
+	unexpectedStatusErr     = "%s returned status code %d"
+)
+
+var (
+	sourceMappingUrlRegex = regexp.MustCompile(`[#@]\s*sourceMappingURL\s*=\s*(\S+)`)
+
+	// quoted absolute URLs, or quoted absolute paths with at least one letter (e.g. "/api/v1/users")
+	endpointRegex = regexp.MustCompile("[\"'`]((?:https?://|/)[A-Za-z0-9_\\-.~%/?=&:@+]*[A-Za-z][A-Za-z0-9_\\-.~%/?=&:@+]*)[\"'`]")
+
+	// prefixes added by bundlers to the paths listed in "sources"
+	bundlerSchemeRegex = regexp.MustCompile(`^[a-z\-]+://`)
+)
+
+// Version 3 source map (https://sourcemaps.info/spec.html). Index maps are flattened into their sections.
+type SourceMap struct {
+	Version        int       `json:"version"`
+	SourceRoot     string    `json:"sourceRoot"`
+	Sources        []string  `json:"sources"`
+	SourcesContent []*string `json:"sourcesContent"`
+	Sections       []struct {
+		Map SourceMap `json:"map"`
+	} `json:"sections"`
+}
+
+// JavaScript file waiting to be checked for a source map, along with the page that loaded it
+type scriptRef struct {
+	script url.URL
+	page   url.URL
+}
+
+// Parses a version 3 source map, merging the sections of index maps
+func ParseSourceMap(data []byte) (SourceMap, error) {
+	var sourceMap SourceMap
+	if err := json.Unmarshal(data, &sourceMap); err != nil {
+		return SourceMap{}, err
+	}
+
+	if sourceMap.Version != 3 {
+		return SourceMap{}, fmt.Errorf(unsupportedSourceMapErr)
+	}
+
+	for _, section := range sourceMap.Sections {
+		sourceMap.Sources = append(sourceMap.Sources, section.Map.Sources...)
+
+		// keeps sourcesContent aligned with sources when a section has no content
+		contents := make([]*string, len(section.Map.Sources))
+		copy(contents, section.Map.SourcesContent)
+		sourceMap.SourcesContent = append(sourceMap.SourcesContent, contents...)
+	}
+
+	return sourceMap, nil
+}
+
+// Returns the source map reference of a JavaScript file, or an empty string if there is none.
+// The SourceMap headers take precedence over the sourceMappingURL comment.
+func FindSourceMapRef(header http.Header, script []byte) string {
+	if ref := header.Get("SourceMap"); ref != "" {
+		return ref
+	}
+
+	if ref := header.Get("X-SourceMap"); ref != "" {
+		return ref
+	}
+
+	// the last comment is the one that applies
+	matches := sourceMappingUrlRegex.FindAllSubmatch(script, -1)
+	if len(matches) > 0 {
+		return string(matches[len(matches)-1][1])
+	}
+
+	return ""
+}
+
+// Decodes an inline source map (e.g. data:application/json;base64,...)
+func decodeDataUri(uri string) ([]byte, error) {
+	metadata, data, found := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !found || !strings.HasSuffix(metadata, ";base64") {
+		return nil, fmt.Errorf(invalidDataUriErr)
+	}
+
+	return base64.StdEncoding.DecodeString(data)
+}
+
+// Finds URLs and absolute paths in quoted strings of JavaScript code
+func ExtractEndpoints(code string) []string {
+	seen := map[string]struct{}{}
+
+	var endpoints []string
+	for _, match := range endpointRegex.FindAllStringSubmatch(code, -1) {
+		endpoint := match[1]
+
+		// protocol-relative URLs and regex-like strings are ignored
+		if strings.HasPrefix(endpoint, "//") {
+			continue
+		}
+
+		if _, exists := seen[endpoint]; exists {
+			continue
+		}
+
+		seen[endpoint] = struct{}{}
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints
+}
+
+// Returns a path relative to the output directory for a source, removing bundler prefixes
+// (e.g. webpack:///./src/app.js -> src/app.js) and any path traversal
+func sourceOutputPath(source string) string {
+	source = bundlerSchemeRegex.ReplaceAllString(source, "")
+	source = strings.ReplaceAll(source, "\\", "/")
+
+	cleaned := filepath.Clean("/" + source)
+	return strings.TrimPrefix(cleaned, "/")
+}
+
+// Returns the src attribute of every <script> tag in a page
+func ExtractScriptSources(node *html.Node) []string {
+	var srcs []string
+
+	var walk func(node *html.Node)
+	walk = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "script" {
+			if src := getAttr(node, "src"); src != "" {
+				srcs = append(srcs, src)
+			}
+		}
+
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+
+	walk(node)
+
+	return srcs
+}
+
+// Queues the scripts loaded by the page to be checked for source maps. Must be called with the crawler's mutex held.
+func (crawler *Crawler) queueScripts(pg page, scriptSrcs []string) {
+	for _, src := range scriptSrcs {
+		ref, err := url.Parse(src)
+		if err != nil {
+			continue
+		}
+
+		script := *pg.url.ResolveReference(ref)
+		if script.Scheme != "http" && script.Scheme != "https" {
+			continue
+		}
+
+		script.Fragment = ""
+		if _, exists := crawler.scriptMap[script.String()]; exists {
+			continue
+		}
+
+		crawler.scriptMap[script.String()] = struct{}{}
+		crawler.scriptQueue = append(crawler.scriptQueue, scriptRef{script: script, page: pg.url})
+	}
+}
+
+// Queues the in-scope scripts loaded by the page to be searched for real-time endpoints and comments once the depth
+// is crawled
+func (crawler *Crawler) queueInScopeScripts(pg page) {
+	if pg.node == nil {
+		return
+	}
+
+	crawler.mutex.Lock()
+	defer crawler.mutex.Unlock()
+
+	for _, src := range ExtractScriptSources(pg.node) {
+		ref, err := url.Parse(src)
+		if err != nil {
+			continue
+		}
+
+		script := *pg.url.ResolveReference(ref)
+		script.Fragment = ""
+		if script.Scheme != "http" && script.Scheme != "https" {
+			continue
+		}
+
+		if !shared.SameBaseDomain(script.Hostname(), crawler.seedUrl.Hostname()) {
+			continue
+		}
+
+		if _, exists := crawler.inScopeScriptMap[script.String()]; exists {
+			continue
+		}
+
+		crawler.inScopeScriptMap[script.String()] = struct{}{}
+		crawler.inScopeScripts = append(crawler.inScopeScripts, scriptRef{script: script, page: pg.url})
+	}
+}
+
+// Fetches every queued in-scope script once, concurrently and respecting the crawler's thread count and delay, and
+// searches it for real-time endpoints and comments
+func (crawler *Crawler) searchQueuedScripts() {
+	crawler.mutex.Lock()
+	queue := crawler.inScopeScripts
+	crawler.inScopeScripts = []scriptRef{}
+	crawler.mutex.Unlock()
+
+	semaphore := make(chan struct{}, crawler.threads)
+	var wg sync.WaitGroup
+
+	for _, ref := range queue {
+		wg.Add(1)
+
+		go func(ref scriptRef) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			reqTime := time.Now()
+			defer crawler.waitDelay(reqTime)
+
+			script, _, err := crawler.fetchBody(ref.script)
+			if err != nil {
+				return
+			}
+
+			if crawler.realtime {
+				crawler.queueRealtimeEndpoints(ExtractRealtimeEndpoints(string(script)), ref.page, ref.script)
+			}
+
+			if crawler.comments != nil {
+				crawler.handleComments(ExtractScriptComments(string(script)), ref.script)
+			}
+		}(ref)
+	}
+
+	wg.Wait()
+}
+
+// Checks every queued script for a source map concurrently, respecting the crawler's thread count and delay
+func (crawler *Crawler) fetchQueuedSourceMaps() {
+	crawler.mutex.Lock()
+	queue := crawler.scriptQueue
+	crawler.scriptQueue = []scriptRef{}
+	crawler.mutex.Unlock()
+
+	semaphore := make(chan struct{}, crawler.threads)
+	var wg sync.WaitGroup
+
+	for _, ref := range queue {
+		wg.Add(1)
+
+		go func(ref scriptRef) {
+			defer wg.Done()
+
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			reqTime := time.Now()
+			crawler.recoverSourceMap(ref)
+
+			crawler.waitDelay(reqTime)
+		}(ref)
+	}
+
+	wg.Wait()
+}
+
+// Fetches the script and its source map, propagates the original source paths, writes the original sources
+// to disk if configured, and feeds the endpoints found in them to the frontier
+func (crawler *Crawler) recoverSourceMap(ref scriptRef) {
+	script, header, err := crawler.fetchBody(ref.script)
+	if err != nil {
+		return
+	}
+
+	mapRef := FindSourceMapRef(header, script)
+
+	var data []byte
+	mapUrl := ref.script
+	if strings.HasPrefix(mapRef, "data:") {
+		data, err = decodeDataUri(mapRef)
+		if err != nil {
+			crawler.propagateWarning(err.Error())
+			return
+		}
+	} else {
+		// falls back to the conventional location next to the script
+		if mapRef == "" {
+			mapRef = ref.script.Path + ".map"
+		}
+
+		parsedRef, err := url.Parse(mapRef)
+		if err != nil {
+			return
+		}
+
+		mapUrl = *ref.script.ResolveReference(parsedRef)
+
+		data, _, err = crawler.fetchBody(mapUrl)
+		if err != nil {
+			return
+		}
+	}
+
+	sourceMap, err := ParseSourceMap(data)
+	if err != nil {
+		return
+	}
+
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.SourceMapFile,
+		Value:  mapUrl.String(),
+		Url:    ref.script,
+		Source: shared.SourceMaps,
+	})
+
+	for i, source := range sourceMap.Sources {
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.OriginalSource,
+			Value:  sourceMap.SourceRoot + source,
+			Url:    mapUrl,
+			Source: shared.SourceMaps,
+		})
+
+		if i >= len(sourceMap.SourcesContent) || sourceMap.SourcesContent[i] == nil {
+			continue
+		}
+
+		content := *sourceMap.SourcesContent[i]
+
+		if crawler.sourceMapDir != "" {
+			crawler.writeSource(mapUrl.Host, source, content)
+		}
+
+		// endpoints are resolved against the page, since scripts are often served from a CDN
+		for _, endpoint := range ExtractEndpoints(content) {
+			crawler.handleFoundUrl(endpoint, ref.page.Host, ref.page.Scheme)
+		}
+	}
+}
+
+// Writes an original source file under <sourceMapDir>/<host>/
+func (crawler *Crawler) writeSource(host string, source string, content string) {
+	path := filepath.Join(crawler.sourceMapDir, host, sourceOutputPath(source))
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		crawler.propagateWarning(err.Error())
+		return
+	}
+
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		crawler.propagateWarning(err.Error())
+	}
+}
+
+// Fetches a resource, returning its body if the response was successful
+func (crawler *Crawler) fetchBody(target url.URL) ([]byte, http.Header, error) {
+	resp, err := crawler.sendRequest(target)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf(unexpectedStatusErr, target.String(), resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return body, resp.Header, nil
+}
@@ -0,0 +1,79 @@
+package osint
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestParseSourceMap(t *testing.T) {
+	data := []byte(`{
+		"version": 3,
+		"sections": [
+			{"offset": {"line": 0, "column": 0}, "map": {"version": 3, "sources": ["webpack:///./src/a.js"], "sourcesContent": ["fetch('/api/a')"]}},
+			{"offset": {"line": 1, "column": 0}, "map": {"version": 3, "sources": ["b.js", "c.js"]}}
+		]
+	}`)
+
+	sourceMap, err := ParseSourceMap(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"webpack:///./src/a.js", "b.js", "c.js"}
+	if !reflect.DeepEqual(sourceMap.Sources, expected) {
+		t.Errorf("ParseSourceMap expected sources %v; got %v", expected, sourceMap.Sources)
+	}
+
+	if len(sourceMap.SourcesContent) != 3 || sourceMap.SourcesContent[0] == nil || sourceMap.SourcesContent[1] != nil {
+		t.Errorf("ParseSourceMap expected sourcesContent aligned with sources; got %v", sourceMap.SourcesContent)
+	}
+
+	if _, err := ParseSourceMap([]byte(`{"version": 2}`)); err == nil {
+		t.Errorf("ParseSourceMap expected error for version 2 source map")
+	}
+}
+
+func TestFindSourceMapRef(t *testing.T) {
+	script := []byte("var a=1;\n//# sourceMappingURL=old.js.map\n//# sourceMappingURL=app.js.map\n")
+
+	if res := FindSourceMapRef(http.Header{}, script); res != "app.js.map" {
+		t.Errorf("FindSourceMapRef expected app.js.map; got %s", res)
+	}
+
+	header := http.Header{}
+	header.Set("SourceMap", "/maps/app.js.map")
+	if res := FindSourceMapRef(header, script); res != "/maps/app.js.map" {
+		t.Errorf("FindSourceMapRef expected header reference; got %s", res)
+	}
+
+	if res := FindSourceMapRef(http.Header{}, []byte("var a=1;")); res != "" {
+		t.Errorf("FindSourceMapRef expected no reference; got %s", res)
+	}
+}
+
+func TestExtractEndpoints(t *testing.T) {
+	code := "const api = '/api/v1/users'; fetch(`/api/v1/users`); axios.get(\"https://internal.example.com/admin?x=1\"); const re = '/'; const cdn = '//cdn.example.com/lib';"
+
+	expected := []string{"/api/v1/users", "https://internal.example.com/admin?x=1"}
+
+	res := ExtractEndpoints(code)
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ExtractEndpoints expected %v; got %v", expected, res)
+	}
+}
+
+func TestSourceOutputPath(t *testing.T) {
+	cases := map[string]string{
+		"webpack:///./src/app.js":      "src/app.js",
+		"../../etc/passwd":             "etc/passwd",
+		"webpack:///webpack/bootstrap": "webpack/bootstrap",
+		"src\\components\\Button.tsx":  "src/components/Button.tsx",
+	}
+
+	for source, expected := range cases {
+		if res := sourceOutputPath(source); res != expected {
+			t.Errorf("sourceOutputPath(%s) expected %s; got %s", source, expected, res)
+		}
+	}
+}
@@ -0,0 +1,469 @@
+package osint
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Errors
+const (
+	noResolversErr = "no DNS resolvers provided"
+)
+
+// Amount of random labels resolved to learn a domain's wildcard answers
+const wildcardProbes = 3
+
+// Amount of resolvers a query is attempted against before giving up
+const maxQueryAttempts = 3
+
+// Subdomain that resolved, along with its records. CNAME holds the whole chain in the order it was followed.
+type ResolvedHost struct {
+	Name  string
+	A     []string
+	AAAA  []string
+	CNAME []string
+}
+
+// Sends the queries of every DNS feature concurrently to a list of resolvers, rotating between the healthy ones at a
+// fixed rate per resolver and caching their answers, and filters out the answers of wildcard DNS records
+type SubdomainResolver struct {
+	upstreams []*upstreamState
+	workers   int
+	next      uint32
+
+	cacheMutex sync.Mutex
+	cache      map[string]cachedAnswer
+
+	mutex     sync.Mutex
+	wildcards map[string]*wildcardAnswers
+}
+
+// Answers of random labels under a domain, computed the first time they are needed
+type wildcardAnswers struct {
+	once    sync.Once
+	answers map[string]struct{}
+}
+
+// Creates a resolver that sends at most rate queries per second to each resolver (no limit if 0) from a pool of
+// workers. Resolvers are parsed with ParseUpstream, so plain (e.g. "1.1.1.1"), DNS-over-TLS and DNS-over-HTTPS
+// resolvers can be mixed.
+func NewSubdomainResolver(resolvers []string, rate int, workers int, timeout time.Duration) (*SubdomainResolver, error) {
+	var upstreams []Upstream
+	for _, resolver := range resolvers {
+		if strings.TrimSpace(resolver) == "" {
+			continue
+		}
+
+		upstream, err := ParseUpstream(resolver, timeout)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s", strings.TrimSpace(resolver), err)
+		}
+
+		upstreams = append(upstreams, upstream)
+	}
+
+	return NewSubdomainResolverWith(upstreams, rate, workers)
+}
+
+// Creates a resolver that sends its queries to the given upstreams, at most rate queries per second to each one
+func NewSubdomainResolverWith(upstreams []Upstream, rate int, workers int) (*SubdomainResolver, error) {
+	if len(upstreams) == 0 {
+		return nil, fmt.Errorf(noResolversErr)
+	}
+
+	resolver := &SubdomainResolver{
+		workers:   max(workers, 1),
+		cache:     map[string]cachedAnswer{},
+		wildcards: map[string]*wildcardAnswers{},
+	}
+
+	for _, upstream := range upstreams {
+		resolver.upstreams = append(resolver.upstreams, newUpstreamState(upstream, rate))
+	}
+
+	return resolver, nil
+}
+
+// Queries the root name servers from every resolver concurrently, and skips the ones that do not answer for a while.
+// Returns the resolvers that failed.
+func (resolver *SubdomainResolver) CheckHealth() []string {
+	failed := make([]bool, len(resolver.upstreams))
+
+	var wg sync.WaitGroup
+	for i, state := range resolver.upstreams {
+		wg.Add(1)
+
+		go func(i int, state *upstreamState) {
+			defer wg.Done()
+
+			msg := new(dns.Msg)
+			msg.SetQuestion(".", dns.TypeNS)
+
+			state.wait()
+			res, err := state.upstream.Exchange(msg)
+			if err != nil || (res.Rcode != dns.RcodeSuccess && res.Rcode != dns.RcodeNameError) {
+				failed[i] = true
+				state.markUnhealthy()
+			}
+		}(i, state)
+	}
+
+	wg.Wait()
+
+	var unhealthy []string
+	for i, state := range resolver.upstreams {
+		if failed[i] {
+			unhealthy = append(unhealthy, state.upstream.String())
+		}
+	}
+
+	return unhealthy
+}
+
+// Resolves every word as a label of the domain, returning the subdomains that exist and are not answered by a
+// wildcard record
+func (resolver *SubdomainResolver) BruteForce(domain string, words []string) []ResolvedHost {
+	domain = strings.TrimSuffix(domain, ".")
+
+	var candidates []string
+	for _, word := range words {
+		label := strings.Trim(word, ".")
+		if label == "" {
+			continue
+		}
+
+		candidates = append(candidates, label+"."+domain)
+	}
+
+	return resolver.ResolveAll(candidates)
+}
+
+// Resolves every name concurrently, returning the ones that exist and are not answered by a wildcard record in
+// the order they were given. Repeated names are resolved once.
+func (resolver *SubdomainResolver) ResolveAll(candidates []string) []ResolvedHost {
+	var names []string
+	seen := map[string]struct{}{}
+	for _, candidate := range candidates {
+		name := strings.TrimSuffix(strings.ToLower(candidate), ".")
+		if _, exists := seen[name]; !exists {
+			seen[name] = struct{}{}
+			names = append(names, name)
+		}
+	}
+
+	results := make([]*ResolvedHost, len(names))
+
+	resolver.runWorkers(len(names), func(i int) {
+		host, exists := resolver.Resolve(names[i])
+		if exists && !resolver.isWildcard(host) {
+			results[i] = &host
+		}
+	})
+
+	var hosts []ResolvedHost
+	for _, host := range results {
+		if host != nil {
+			hosts = append(hosts, *host)
+		}
+	}
+
+	return hosts
+}
+
+// Calls fn for every index from 0 to n from the resolver's pool of workers
+func (resolver *SubdomainResolver) runWorkers(n int, fn func(i int)) {
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+
+	for w := 0; w < resolver.workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range indexes {
+				fn(i)
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+
+	wg.Wait()
+}
+
+// Resolves the A, AAAA and CNAME records of a name. Returns false if the name does not exist or has no records.
+// Names whose CNAME chain ends at a name that does not exist are kept with their chain, since resolvers answer them
+// with NXDOMAIN (RFC 6604) and they are the candidates for subdomain takeovers.
+func (resolver *SubdomainResolver) Resolve(name string) (ResolvedHost, bool) {
+	host := ResolvedHost{Name: strings.TrimSuffix(strings.ToLower(name), ".")}
+
+	res, err := resolver.query(host.Name, dns.TypeA)
+	if err != nil {
+		return host, false
+	}
+
+	for _, rr := range res.Answer {
+		switch record := rr.(type) {
+		case *dns.CNAME:
+			host.CNAME = append(host.CNAME, strings.TrimSuffix(record.Target, "."))
+		case *dns.A:
+			host.A = append(host.A, record.A.String())
+		}
+	}
+
+	if res.Rcode == dns.RcodeNameError {
+		return host, len(host.CNAME) > 0
+	}
+
+	res, err = resolver.query(host.Name, dns.TypeAAAA)
+	if err == nil {
+		for _, rr := range res.Answer {
+			if record, ok := rr.(*dns.AAAA); ok {
+				host.AAAA = append(host.AAAA, record.AAAA.String())
+			}
+		}
+	}
+
+	return host, len(host.A) > 0 || len(host.AAAA) > 0 || len(host.CNAME) > 0
+}
+
+// Returns the IPv4 and IPv6 addresses of a name
+func (resolver *SubdomainResolver) addresses(name string) []string {
+	var addresses []string
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		res, err := resolver.query(name, qtype)
+		if err != nil {
+			continue
+		}
+
+		for _, rr := range res.Answer {
+			switch record := rr.(type) {
+			case *dns.A:
+				addresses = append(addresses, record.A.String())
+			case *dns.AAAA:
+				addresses = append(addresses, record.AAAA.String())
+			}
+		}
+	}
+
+	return addresses
+}
+
+// Checks if the domain answers random labels, which means it has a wildcard record
+func (resolver *SubdomainResolver) IsWildcardDomain(domain string) bool {
+	return len(resolver.wildcardAnswers(strings.TrimSuffix(strings.ToLower(domain), "."))) > 0
+}
+
+// Checks if every answer of the host is also an answer to random labels of its parent domain
+func (resolver *SubdomainResolver) isWildcard(host ResolvedHost) bool {
+	_, parent, found := strings.Cut(host.Name, ".")
+	if !found {
+		return false
+	}
+
+	wildcard := resolver.wildcardAnswers(parent)
+	if len(wildcard) == 0 {
+		return false
+	}
+
+	// wildcards that point to a CNAME answer with the same chain for every label
+	if len(host.CNAME) > 0 {
+		if _, exists := wildcard["CNAME "+host.CNAME[len(host.CNAME)-1]]; exists {
+			return true
+		}
+	}
+
+	addresses := append(append([]string{}, host.A...), host.AAAA...)
+	if len(addresses) == 0 {
+		return false
+	}
+
+	for _, address := range addresses {
+		if _, exists := wildcard[address]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Returns the answers to random labels of the domain, resolving them the first time the domain is seen
+func (resolver *SubdomainResolver) wildcardAnswers(domain string) map[string]struct{} {
+	resolver.mutex.Lock()
+	wildcard, exists := resolver.wildcards[domain]
+	if !exists {
+		wildcard = &wildcardAnswers{answers: map[string]struct{}{}}
+		resolver.wildcards[domain] = wildcard
+	}
+	resolver.mutex.Unlock()
+
+	wildcard.once.Do(func() {
+		for i := 0; i < wildcardProbes; i++ {
+			host, exists := resolver.Resolve(randomPath() + "." + domain)
+			if !exists {
+				continue
+			}
+
+			for _, address := range append(append([]string{}, host.A...), host.AAAA...) {
+				wildcard.answers[address] = struct{}{}
+			}
+
+			if len(host.CNAME) > 0 {
+				wildcard.answers["CNAME "+host.CNAME[len(host.CNAME)-1]] = struct{}{}
+			}
+		}
+	})
+
+	return wildcard.answers
+}
+
+// Sends a recursive query, moving on to the next resolver if one fails
+func (resolver *SubdomainResolver) query(name string, qtype uint16) (*dns.Msg, error) {
+	res, _, err := resolver.queryWithServer(name, qtype)
+	return res, err
+}
+
+// Sends a recursive query, returning the resolver that answered it
+func (resolver *SubdomainResolver) queryWithServer(name string, qtype uint16) (*dns.Msg, string, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+
+	return resolver.exchange(msg)
+}
+
+// Sends a message to the next healthy resolver, moving on to the following one if it fails. Answers with records are
+// cached until they expire, up to maxCacheEntries answers.
+func (resolver *SubdomainResolver) exchange(msg *dns.Msg) (*dns.Msg, string, error) {
+	key, cacheable := cacheKey(msg)
+	if cacheable {
+		if res, server, found := resolver.cached(key, msg.Id); found {
+			return res, server, nil
+		}
+	}
+
+	var err error
+	for attempt := 0; attempt < min(maxQueryAttempts, len(resolver.upstreams)+1); attempt++ {
+		state := resolver.nextUpstream()
+		state.wait()
+
+		var res *dns.Msg
+		res, err = state.upstream.Exchange(msg)
+		state.record(err)
+
+		if err != nil {
+			continue
+		}
+
+		// servers that fail to answer are retried with another resolver
+		if res.Rcode != dns.RcodeSuccess && res.Rcode != dns.RcodeNameError {
+			err = fmt.Errorf(unexpectedResponseErr)
+			continue
+		}
+
+		if cacheable {
+			resolver.store(key, res, state.upstream.String())
+		}
+
+		return res, state.upstream.String(), nil
+	}
+
+	return nil, "", err
+}
+
+// Returns the next resolver in rotation that is healthy, or the next one if none are
+func (resolver *SubdomainResolver) nextUpstream() *upstreamState {
+	start := int(atomic.AddUint32(&resolver.next, 1))
+	for i := 0; i < len(resolver.upstreams); i++ {
+		state := resolver.upstreams[(start+i)%len(resolver.upstreams)]
+		if state.healthy() {
+			return state
+		}
+	}
+
+	return resolver.upstreams[start%len(resolver.upstreams)]
+}
+
+// Returns a copy of the cached answer with the ID of the query it answers
+func (resolver *SubdomainResolver) cached(key string, id uint16) (*dns.Msg, string, bool) {
+	resolver.cacheMutex.Lock()
+	defer resolver.cacheMutex.Unlock()
+
+	answer, exists := resolver.cache[key]
+	if !exists {
+		return nil, "", false
+	}
+
+	if time.Now().After(answer.expires) {
+		delete(resolver.cache, key)
+		return nil, "", false
+	}
+
+	res := answer.msg.Copy()
+	res.Id = id
+
+	return res, answer.server, true
+}
+
+func (resolver *SubdomainResolver) store(key string, res *dns.Msg, server string) {
+	ttl := cacheTtl(res)
+	if ttl <= 0 {
+		return
+	}
+
+	resolver.cacheMutex.Lock()
+	defer resolver.cacheMutex.Unlock()
+
+	if _, exists := resolver.cache[key]; !exists && len(resolver.cache) >= maxCacheEntries {
+		resolver.evict()
+	}
+
+	resolver.cache[key] = cachedAnswer{msg: res.Copy(), server: server, expires: time.Now().Add(ttl)}
+}
+
+// Makes room in the cache by dropping the expired answers, or the answer closest to expiring if none have expired.
+// Must be called with the cache mutex held.
+func (resolver *SubdomainResolver) evict() {
+	now := time.Now()
+	oldest := ""
+	for key, answer := range resolver.cache {
+		if now.After(answer.expires) {
+			delete(resolver.cache, key)
+			continue
+		}
+
+		if oldest == "" || answer.expires.Before(resolver.cache[oldest].expires) {
+			oldest = key
+		}
+	}
+
+	if len(resolver.cache) >= maxCacheEntries {
+		delete(resolver.cache, oldest)
+	}
+}
+
+// Formats the host with its records (e.g. "api.example.com (CNAME app.example.net, A 192.0.2.1)")
+func (host ResolvedHost) String() string {
+	var records []string
+	if len(host.CNAME) > 0 {
+		records = append(records, "CNAME "+strings.Join(host.CNAME, " -> "))
+	}
+
+	if len(host.A) > 0 {
+		records = append(records, "A "+strings.Join(host.A, ", "))
+	}
+
+	if len(host.AAAA) > 0 {
+		records = append(records, "AAAA "+strings.Join(host.AAAA, ", "))
+	}
+
+	return host.Name + " (" + strings.Join(records, "; ") + ")"
+}
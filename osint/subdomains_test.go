@@ -0,0 +1,184 @@
+package osint
+
+import (
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Starts a UDP DNS server on a local port answering from the records, where "*" labels match any name under them
+func startStubResolver(t *testing.T, lines ...string) string {
+	var records []dns.RR
+	for _, line := range lines {
+		rr, err := dns.NewRR(line)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records = append(records, rr)
+	}
+
+	lookup := func(name string) []dns.RR {
+		var answers []dns.RR
+		for _, rr := range records {
+			if rr.Header().Name == name {
+				answers = append(answers, rr)
+			}
+		}
+
+		if len(answers) > 0 {
+			return answers
+		}
+
+		for _, rr := range records {
+			wildcard := rr.Header().Name
+			if strings.HasPrefix(wildcard, "*.") && strings.HasSuffix(name, wildcard[1:]) {
+				copied := dns.Copy(rr)
+				copied.Header().Name = name
+				answers = append(answers, copied)
+			}
+		}
+
+		return answers
+	}
+
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		res := new(dns.Msg)
+		res.SetReply(req)
+
+		question := req.Question[0]
+		name := question.Name
+
+		answers := lookup(name)
+		if len(answers) == 0 {
+			res.Rcode = dns.RcodeNameError
+		}
+
+		// CNAMEs are followed, as a recursive resolver would
+		for len(answers) > 0 {
+			var cname *dns.CNAME
+			for _, rr := range answers {
+				if rr.Header().Rrtype == question.Qtype {
+					res.Answer = append(res.Answer, rr)
+				} else if c, ok := rr.(*dns.CNAME); ok {
+					res.Answer = append(res.Answer, rr)
+					cname = c
+				}
+			}
+
+			if cname == nil {
+				break
+			}
+
+			// chains that end at a name that does not exist are answered with NXDOMAIN (RFC 6604)
+			answers = lookup(cname.Target)
+			if len(answers) == 0 {
+				res.Rcode = dns.RcodeNameError
+			}
+		}
+
+		w.WriteMsg(res)
+	})
+
+	return serveDns(t, handler)
+}
+
+// Starts a UDP DNS server on a local port, returning its address
+func serveDns(t *testing.T, handler dns.Handler) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &dns.Server{PacketConn: conn, Handler: handler}
+	go server.ActivateAndServe()
+	t.Cleanup(func() { server.Shutdown() })
+
+	return conn.LocalAddr().String()
+}
+
+func TestSubdomainBruteForce(t *testing.T) {
+	addr := startStubResolver(t,
+		"api.example.com. 300 IN A 192.0.2.1",
+		"api.example.com. 300 IN AAAA 2001:db8::1",
+		"www.example.com. 300 IN CNAME edge.cdn.example.net.",
+		"edge.cdn.example.net. 300 IN A 198.51.100.7",
+		"old.example.com. 300 IN CNAME deleted-bucket.s3.amazonaws.com.",
+		"app.dev.example.com. 300 IN A 192.0.2.20",
+		"*.dev.example.com. 300 IN A 192.0.2.99",
+		"test.dev.example.com. 300 IN A 192.0.2.99",
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 4, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []ResolvedHost{
+		{Name: "api.example.com", A: []string{"192.0.2.1"}, AAAA: []string{"2001:db8::1"}},
+		{Name: "www.example.com", A: []string{"198.51.100.7"}, CNAME: []string{"edge.cdn.example.net"}},
+		// dangling CNAMEs are answered with NXDOMAIN, and kept for the takeover checks
+		{Name: "old.example.com", CNAME: []string{"deleted-bucket.s3.amazonaws.com"}},
+	}
+
+	res := resolver.BruteForce("example.com", []string{"api", "www", "old", "mail", "API"})
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("BruteForce expected %v; got %v", expected, res)
+	}
+
+	// names answered like random labels are filtered out
+	expected = []ResolvedHost{{Name: "app.dev.example.com", A: []string{"192.0.2.20"}}}
+
+	res = resolver.BruteForce("dev.example.com", []string{"app", "test", "anything"})
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("BruteForce expected %v; got %v", expected, res)
+	}
+
+	if !resolver.IsWildcardDomain("dev.example.com") || resolver.IsWildcardDomain("example.com") {
+		t.Errorf("IsWildcardDomain expected wildcard only for dev.example.com")
+	}
+}
+
+func TestNewSubdomainResolver(t *testing.T) {
+	resolver, err := NewSubdomainResolver([]string{
+		"1.1.1.1", " 9.9.9.9:5353", "2606:4700:4700::1111", "", "tcp://8.8.8.8", "tls://dns.quad9.net",
+		"https://cloudflare-dns.com/dns-query",
+	}, 10, 0, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var upstreams []string
+	for _, state := range resolver.upstreams {
+		upstreams = append(upstreams, state.upstream.String())
+	}
+
+	expected := []string{
+		"1.1.1.1:53", "9.9.9.9:5353", "[2606:4700:4700::1111]:53", "tcp://8.8.8.8:53", "tls://dns.quad9.net:853",
+		"https://cloudflare-dns.com/dns-query",
+	}
+	if !reflect.DeepEqual(upstreams, expected) {
+		t.Errorf("NewSubdomainResolver expected %v; got %v", expected, upstreams)
+	}
+
+	if _, err := NewSubdomainResolver([]string{""}, 10, 1, time.Second); err == nil {
+		t.Errorf("NewSubdomainResolver expected error for empty resolver list")
+	}
+
+	if _, err := NewSubdomainResolver([]string{"quic://1.1.1.1"}, 10, 1, time.Second); err == nil {
+		t.Errorf("NewSubdomainResolver expected error for unsupported transport")
+	}
+}
+
+func TestResolvedHostString(t *testing.T) {
+	host := ResolvedHost{Name: "www.example.com", A: []string{"192.0.2.1", "192.0.2.2"}, CNAME: []string{"a.example.net", "b.example.net"}}
+	expected := "www.example.com (CNAME a.example.net -> b.example.net; A 192.0.2.1, 192.0.2.2)"
+
+	if res := host.String(); res != expected {
+		t.Errorf("String expected %q; got %q", expected, res)
+	}
+}
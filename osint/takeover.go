@@ -0,0 +1,253 @@
+package osint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Errors
+const (
+	noTakeoverFingerprintsErr = "takeover fingerprints file contains no vulnerable services"
+)
+
+// Maximum amount of CNAMEs followed from a subdomain
+const maxCnameHops = 10
+
+// Takeover statuses
+const (
+	Vulnerable       = "VULNERABLE"
+	LikelyVulnerable = "LIKELY_VULNERABLE"
+)
+
+// Error page of a provider that shows a resource can be claimed by anyone
+type TakeoverFingerprint struct {
+	Service    string   `json:"service"`
+	Cnames     []string `json:"cname"`
+	Body       string   `json:"fingerprint"`
+	HttpStatus int      `json:"http_status"`
+	Nxdomain   bool     `json:"nxdomain"`
+	Status     string   `json:"status"`
+	Vulnerable bool     `json:"vulnerable"`
+}
+
+// Detects subdomains whose CNAME points to an unclaimed resource of a third-party provider
+type TakeoverDetector struct {
+	fingerprints []TakeoverFingerprint
+	fetch        func(host string) (int, string, error)
+}
+
+// Subdomain that can likely be taken over, along with the evidence found
+type TakeoverResult struct {
+	Host     string
+	Chain    []string
+	Service  string
+	Status   string
+	Evidence string
+}
+
+// Loads a fingerprints file in the can-i-take-over-xyz format (a list of services with their CNAME suffixes and
+// error page fingerprints). Services that are not vulnerable are skipped, and edge cases are only reported as
+// likely vulnerable.
+func LoadTakeoverFingerprints(path string) (TakeoverDetector, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return TakeoverDetector{}, err
+	}
+
+	var raw []TakeoverFingerprint
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return TakeoverDetector{}, err
+	}
+
+	return NewTakeoverDetector(raw)
+}
+
+// Creates a detector from fingerprints, keeping the ones of vulnerable services and edge cases
+func NewTakeoverDetector(fingerprints []TakeoverFingerprint) (TakeoverDetector, error) {
+	var kept []TakeoverFingerprint
+	for _, fp := range fingerprints {
+		if !fp.Vulnerable && !strings.EqualFold(fp.Status, "Edge case") {
+			continue
+		}
+
+		var cnames []string
+		for _, cname := range fp.Cnames {
+			cnames = append(cnames, strings.ToLower(strings.Trim(cname, ".")))
+		}
+		fp.Cnames = cnames
+
+		kept = append(kept, fp)
+	}
+
+	if len(kept) == 0 {
+		return TakeoverDetector{}, fmt.Errorf(noTakeoverFingerprintsErr)
+	}
+
+	return TakeoverDetector{fingerprints: kept, fetch: fetchTakeoverPage}, nil
+}
+
+// Follows the CNAME chain of every host concurrently, and checks if the chain ends in a name that does not exist or
+// in a provider's error page. Returns the hosts that are vulnerable or likely vulnerable in the order they were given.
+func (detector TakeoverDetector) Check(resolver *SubdomainResolver, hosts []string) []TakeoverResult {
+	results := make([]*TakeoverResult, len(hosts))
+
+	resolver.runWorkers(len(hosts), func(i int) {
+		results[i] = detector.checkHost(resolver, hosts[i])
+	})
+
+	var vulnerable []TakeoverResult
+	for _, result := range results {
+		if result != nil {
+			vulnerable = append(vulnerable, *result)
+		}
+	}
+
+	return vulnerable
+}
+
+func (detector TakeoverDetector) checkHost(resolver *SubdomainResolver, host string) *TakeoverResult {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	chain, dangling := followCnames(resolver, host)
+	if len(chain) == 0 {
+		return nil
+	}
+
+	result := TakeoverResult{Host: host, Chain: chain}
+	fp, matched := detector.match(chain)
+	if matched {
+		result.Service = fp.Service
+	}
+
+	status := Vulnerable
+	if matched && !fp.Vulnerable {
+		status = LikelyVulnerable
+	}
+
+	if dangling {
+		// a CNAME to a name that does not exist can be claimed with any provider that lets its users pick the name
+		result.Status = status
+		if !matched || !fp.Nxdomain {
+			result.Status = LikelyVulnerable
+		}
+
+		result.Evidence = fmt.Sprintf("CNAME %s -> %s does not exist (NXDOMAIN)", host, strings.Join(chain, " -> "))
+		return &result
+	}
+
+	if !matched || fp.Body == "" || fp.Nxdomain {
+		return nil
+	}
+
+	code, body, err := detector.fetch(host)
+	if err != nil || !strings.Contains(body, fp.Body) || (fp.HttpStatus != 0 && code != fp.HttpStatus) {
+		return nil
+	}
+
+	result.Status = status
+	result.Evidence = fmt.Sprintf("CNAME %s -> %s; HTTP %d response matches %q", host, strings.Join(chain, " -> "), code, fp.Body)
+
+	return &result
+}
+
+// Returns the fingerprint of the first provider any name of the chain belongs to
+func (detector TakeoverDetector) match(chain []string) (TakeoverFingerprint, bool) {
+	for _, name := range chain {
+		for _, fp := range detector.fingerprints {
+			for _, cname := range fp.Cnames {
+				if name == cname || strings.HasSuffix(name, "."+cname) {
+					return fp, true
+				}
+			}
+		}
+	}
+
+	return TakeoverFingerprint{}, false
+}
+
+// Follows the CNAME records of a host one at a time, returning the targets in order and whether the last target
+// does not exist
+func followCnames(resolver *SubdomainResolver, host string) ([]string, bool) {
+	var chain []string
+
+	current := host
+	for hop := 0; hop < maxCnameHops; hop++ {
+		res, err := resolver.query(current, dns.TypeCNAME)
+		if err != nil {
+			return chain, false
+		}
+
+		var target string
+		for _, rr := range res.Answer {
+			if cname, ok := rr.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, dns.Fqdn(current)) {
+				target = strings.ToLower(strings.TrimSuffix(cname.Target, "."))
+			}
+		}
+
+		// some resolvers chase the chain and answer NXDOMAIN along with the CNAMEs that lead to the missing name
+		if target == "" {
+			if res.Rcode == dns.RcodeNameError {
+				return chain, len(chain) > 0
+			}
+
+			break
+		}
+
+		chain = append(chain, target)
+		current = target
+	}
+
+	if len(chain) == 0 {
+		return chain, false
+	}
+
+	// the last target has no CNAME of its own, so it is checked for addresses
+	res, err := resolver.query(current, dns.TypeA)
+	if err != nil {
+		return chain, false
+	}
+
+	return chain, res.Rcode == dns.RcodeNameError
+}
+
+// Fetches the page served on the host, over HTTPS first and then over HTTP
+func fetchTakeoverPage(host string) (int, string, error) {
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	var err error
+	for _, scheme := range []string{"https", "http"} {
+		var resp *http.Response
+		resp, err = client.Get(scheme + "://" + host + "/")
+		if err != nil {
+			continue
+		}
+
+		body, readErr := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+		resp.Body.Close()
+
+		if readErr != nil {
+			err = readErr
+			continue
+		}
+
+		return resp.StatusCode, string(body), nil
+	}
+
+	return 0, "", err
+}
+
+func (result TakeoverResult) String() string {
+	service := result.Service
+	if service == "" {
+		service = "unknown provider"
+	}
+
+	return fmt.Sprintf("%s (%s): %s", result.Host, service, result.Evidence)
+}
@@ -0,0 +1,87 @@
+package osint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTakeoverCheck(t *testing.T) {
+	addr := startStubResolver(t,
+		"docs.example.com. 300 IN CNAME example-org.github.io.",
+		"example-org.github.io. 300 IN A 185.199.108.153",
+		"shop.example.com. 300 IN CNAME shop.myshopify.com.",
+		"shop.myshopify.com. 300 IN A 23.227.38.65",
+		"assets.example.com. 300 IN CNAME assets.example.com.s3.amazonaws.com.",
+		"old.example.com. 300 IN CNAME old-app.example-cloud.net.",
+		"www.example.com. 300 IN A 192.0.2.1",
+		"blog.example.com. 300 IN CNAME example-org.github.io.",
+	)
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 2, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	detector, err := NewTakeoverDetector([]TakeoverFingerprint{
+		{Service: "GitHub Pages", Cnames: []string{"github.io"}, Body: "There isn't a GitHub Pages site here.", HttpStatus: 404, Vulnerable: true},
+		{Service: "AWS/S3", Cnames: []string{"s3.amazonaws.com"}, Body: "The specified bucket does not exist", Vulnerable: true},
+		{Service: "Shopify", Cnames: []string{"myshopify.com"}, Body: "Sorry, this shop is currently unavailable.", Status: "Edge case"},
+		{Service: "Cloudflare", Cnames: []string{"cloudflare.net"}, Status: "Not vulnerable"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pages := map[string]string{
+		"docs.example.com": "There isn't a GitHub Pages site here.",
+		"blog.example.com": "<html>Welcome to the blog</html>",
+		"shop.example.com": "Sorry, this shop is currently unavailable.",
+	}
+	detector.fetch = func(host string) (int, string, error) {
+		return 404, pages[host], nil
+	}
+
+	res := detector.Check(resolver, []string{"docs.example.com", "shop.example.com", "assets.example.com", "old.example.com", "www.example.com", "blog.example.com"})
+
+	var statuses [][]string
+	for _, result := range res {
+		statuses = append(statuses, []string{result.Host, result.Service, result.Status})
+	}
+
+	expected := [][]string{
+		{"docs.example.com", "GitHub Pages", Vulnerable},
+		{"shop.example.com", "Shopify", LikelyVulnerable},
+		{"assets.example.com", "AWS/S3", LikelyVulnerable},
+		{"old.example.com", "", LikelyVulnerable},
+	}
+
+	if !reflect.DeepEqual(statuses, expected) {
+		t.Errorf("Check expected %v; got %v", expected, statuses)
+	}
+
+	expectedEvidence := "CNAME old.example.com -> old-app.example-cloud.net does not exist (NXDOMAIN)"
+	if len(res) == 4 && res[3].Evidence != expectedEvidence {
+		t.Errorf("Check expected evidence %q; got %q", expectedEvidence, res[3].Evidence)
+	}
+}
+
+func TestLoadTakeoverFingerprints(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprints.json")
+	data := `[
+		{"cname": ["github.io"], "fingerprint": "There isn't a GitHub Pages site here.", "http_status": 404, "nxdomain": false, "service": "GitHub Pages", "status": "Vulnerable", "vulnerable": true},
+		{"cname": ["cloudfront.net"], "fingerprint": "Bad request", "http_status": null, "nxdomain": false, "service": "AWS/CloudFront", "status": "Not vulnerable", "vulnerable": false}
+	]`
+	os.WriteFile(path, []byte(data), 0644)
+
+	detector, err := LoadTakeoverFingerprints(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(detector.fingerprints) != 1 || detector.fingerprints[0].Service != "GitHub Pages" {
+		t.Errorf("LoadTakeoverFingerprints expected only the vulnerable GitHub Pages fingerprint; got %v", detector.fingerprints)
+	}
+}
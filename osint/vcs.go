@@ -0,0 +1,555 @@
+package osint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	invalidGitIndexErr = "file is not a git index"
+	invalidDSStoreErr  = "file is not a .DS_Store"
+	noSvnEntriesErr    = "file has no Subversion entries"
+	truncatedFileErr   = "file is truncated"
+	unknownRecordErr   = ".DS_Store record has unknown data type %q"
+)
+
+// Maximum depth of the .DS_Store B-tree, which protects against cyclic nodes
+const maxDSStoreDepth = 32
+
+var (
+	gitHeadRegex   = regexp.MustCompile(`^(ref: refs/|[0-9a-f]{40}\s*$)`)
+	gitRemoteRegex = regexp.MustCompile(`(?m)^\s*url\s*=\s*(\S+)`)
+)
+
+// Returns the paths tracked by a git index file (.git/index). Versions 2, 3 and 4 are supported.
+func ParseGitIndex(data []byte) ([]string, error) {
+	if len(data) < 12 || string(data[:4]) != "DIRC" {
+		return nil, fmt.Errorf(invalidGitIndexErr)
+	}
+
+	version := binary.BigEndian.Uint32(data[4:8])
+	if version < 2 || version > 4 {
+		return nil, fmt.Errorf(invalidGitIndexErr)
+	}
+
+	count := binary.BigEndian.Uint32(data[8:12])
+
+	var paths []string
+	offset := 12
+	previous := ""
+	for i := uint32(0); i < count; i++ {
+		// ctime, mtime, dev, ino, mode, uid, gid, size, sha-1 and flags
+		entryStart := offset
+		offset += 62
+		if offset > len(data) {
+			return paths, fmt.Errorf(truncatedFileErr)
+		}
+
+		flags := binary.BigEndian.Uint16(data[offset-2 : offset])
+		if version >= 3 && flags&0x4000 != 0 {
+			offset += 2
+		}
+
+		var path string
+		if version == 4 {
+			// the path is stored as the amount of bytes removed from the previous path and a new suffix
+			removed, n := gitVarint(data[min(offset, len(data)):])
+			if n == 0 || removed < 0 || removed > len(previous) {
+				return paths, fmt.Errorf(truncatedFileErr)
+			}
+			offset += n
+
+			end := bytes.IndexByte(data[min(offset, len(data)):], 0)
+			if end == -1 {
+				return paths, fmt.Errorf(truncatedFileErr)
+			}
+
+			path = previous[:len(previous)-removed] + string(data[offset:offset+end])
+			offset += end + 1
+		} else {
+			end := bytes.IndexByte(data[min(offset, len(data)):], 0)
+			if end == -1 {
+				return paths, fmt.Errorf(truncatedFileErr)
+			}
+
+			path = string(data[offset : offset+end])
+
+			// entries are padded with 1 to 8 null bytes to a multiple of 8
+			offset = entryStart + (offset-entryStart+end+8)/8*8
+		}
+
+		paths = append(paths, path)
+		previous = path
+	}
+
+	return paths, nil
+}
+
+// Decodes the variable-length integers used by index version 4, returning the value and the bytes read. Values that
+// do not fit in an int are treated as invalid, and 0 bytes are returned for them.
+func gitVarint(data []byte) (int, int) {
+	if len(data) == 0 {
+		return 0, 0
+	}
+
+	value := int(data[0] & 0x7f)
+	n := 1
+	for data[n-1]&0x80 != 0 {
+		if n == len(data) || value >= math.MaxInt>>7 {
+			return 0, 0
+		}
+
+		value = ((value + 1) << 7) | int(data[n]&0x7f)
+		n++
+	}
+
+	return value, n
+}
+
+// Returns the entries of a Subversion working copy created before 1.7 (.svn/entries). Directories end with a slash.
+func ParseSvnEntries(data []byte) ([]string, error) {
+	// the first chunk describes the directory itself
+	chunks := strings.Split(string(data), "\f\n")
+	if len(chunks) < 2 {
+		return nil, fmt.Errorf(noSvnEntriesErr)
+	}
+
+	var entries []string
+	for _, chunk := range chunks[1:] {
+		lines := strings.SplitN(chunk, "\n", 3)
+		if len(lines) < 2 || lines[0] == "" {
+			continue
+		}
+
+		switch lines[1] {
+		case "file":
+			entries = append(entries, lines[0])
+		case "dir":
+			entries = append(entries, lines[0]+"/")
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(noSvnEntriesErr)
+	}
+
+	return entries, nil
+}
+
+// Returns the tracked paths listed in a Mercurial fncache (.hg/store/fncache)
+func ParseHgFncache(data []byte) []string {
+	seen := map[string]struct{}{}
+
+	var paths []string
+	for _, line := range strings.Split(string(data), "\n") {
+		path, found := strings.CutPrefix(line, "data/")
+		if !found {
+			continue
+		}
+
+		path = strings.TrimSuffix(strings.TrimSuffix(path, ".i"), ".d")
+		path = decodeHgPath(path)
+
+		if _, exists := seen[path]; exists {
+			continue
+		}
+
+		seen[path] = struct{}{}
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+// Reverses Mercurial's store encoding, where "_x" is an uppercase letter, "__" is an underscore and "~xx" is a
+// hex-encoded byte. Directories ending in ".i", ".d" or ".hg" have ".hg" appended.
+func decodeHgPath(path string) string {
+	var decoded strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '_' && i+1 < len(path):
+			if path[i+1] == '_' {
+				decoded.WriteByte('_')
+			} else {
+				decoded.WriteString(strings.ToUpper(path[i+1 : i+2]))
+			}
+			i++
+		case path[i] == '~' && i+2 < len(path):
+			if b, err := hex.DecodeString(path[i+1 : i+3]); err == nil {
+				decoded.Write(b)
+				i += 2
+				continue
+			}
+			decoded.WriteByte(path[i])
+		default:
+			decoded.WriteByte(path[i])
+		}
+	}
+
+	replacer := strings.NewReplacer(".i.hg/", ".i/", ".d.hg/", ".d/", ".hg.hg/", ".hg/")
+	return replacer.Replace(decoded.String())
+}
+
+// Reads big-endian values from a .DS_Store block, recording the first out of bounds read. Reads past the end of the
+// block return nil, so lengths read from the file are never allocated before they are checked against the block.
+type dsReader struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+func (r *dsReader) read(n int) []byte {
+	if r.err != nil || n < 0 || n > len(r.data)-r.offset {
+		r.err = fmt.Errorf(truncatedFileErr)
+		return nil
+	}
+
+	b := r.data[r.offset : r.offset+n]
+	r.offset += n
+
+	return b
+}
+
+func (r *dsReader) uint8() uint8 {
+	b := r.read(1)
+	if b == nil {
+		return 0
+	}
+
+	return b[0]
+}
+
+func (r *dsReader) uint32() uint32 {
+	b := r.read(4)
+	if b == nil {
+		return 0
+	}
+
+	return binary.BigEndian.Uint32(b)
+}
+
+// Returns the names of the files listed in a .DS_Store file, in the order they are stored
+func ParseDSStore(data []byte) ([]string, error) {
+	if len(data) < 36 || binary.BigEndian.Uint32(data[:4]) != 1 || string(data[4:8]) != "Bud1" {
+		return nil, fmt.Errorf(invalidDSStoreErr)
+	}
+
+	// offsets are relative to the end of the 4-byte alignment header
+	buddy := data[4:]
+
+	header := &dsReader{data: buddy, offset: 4}
+	rootOffset := int(header.uint32())
+	rootSize := int(header.uint32())
+	if rootOffset+rootSize > len(buddy) {
+		return nil, fmt.Errorf(truncatedFileErr)
+	}
+
+	root := &dsReader{data: buddy[rootOffset : rootOffset+rootSize]}
+
+	blockCount := int(root.uint32())
+	root.read(4)
+	if blockCount > len(root.data)/4 {
+		return nil, fmt.Errorf(truncatedFileErr)
+	}
+
+	addresses := make([]uint32, 0, blockCount)
+	for i := 0; i < blockCount && root.err == nil; i++ {
+		addresses = append(addresses, root.uint32())
+	}
+
+	// the address table is padded to a multiple of 256 entries
+	if blockCount%256 != 0 {
+		root.read((256 - blockCount%256) * 4)
+	}
+
+	dsdb := -1
+	tocCount := int(root.uint32())
+	for i := 0; i < tocCount && root.err == nil; i++ {
+		name := string(root.read(int(root.uint8())))
+		id := int(root.uint32())
+		if name == "DSDB" {
+			dsdb = id
+		}
+	}
+
+	if root.err != nil {
+		return nil, root.err
+	}
+
+	if dsdb == -1 {
+		return nil, fmt.Errorf(invalidDSStoreErr)
+	}
+
+	block := func(id uint32) (*dsReader, error) {
+		if int(id) >= len(addresses) {
+			return nil, fmt.Errorf(truncatedFileErr)
+		}
+
+		address := addresses[id]
+		offset := int(address &^ 0x1f)
+		size := 1 << (address & 0x1f)
+		if offset+size > len(buddy) {
+			return nil, fmt.Errorf(truncatedFileErr)
+		}
+
+		return &dsReader{data: buddy[offset : offset+size]}, nil
+	}
+
+	db, err := block(uint32(dsdb))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	var names []string
+
+	// every block of a valid B-tree has a single parent, so blocks reached twice are rejected before the walk can
+	// grow exponentially
+	visited := map[uint32]struct{}{}
+
+	var walk func(id uint32, depth int) error
+	walk = func(id uint32, depth int) error {
+		if depth > maxDSStoreDepth {
+			return fmt.Errorf(invalidDSStoreErr)
+		}
+
+		if _, exists := visited[id]; exists {
+			return fmt.Errorf(invalidDSStoreErr)
+		}
+		visited[id] = struct{}{}
+
+		node, err := block(id)
+		if err != nil {
+			return err
+		}
+
+		// leaf nodes have no rightmost child, and internal nodes have a child before each record
+		next := node.uint32()
+		count := int(node.uint32())
+		for i := 0; i < count && node.err == nil; i++ {
+			if next != 0 {
+				if err := walk(node.uint32(), depth+1); err != nil {
+					return err
+				}
+			}
+
+			name, err := readDSRecord(node)
+			if err != nil {
+				return err
+			}
+
+			if _, exists := seen[name]; !exists && name != "." {
+				seen[name] = struct{}{}
+				names = append(names, name)
+			}
+		}
+
+		if node.err != nil {
+			return node.err
+		}
+
+		if next != 0 {
+			return walk(next, depth+1)
+		}
+
+		return nil
+	}
+
+	if err := walk(db.uint32(), 0); err != nil {
+		return names, err
+	}
+
+	return names, nil
+}
+
+// Reads a single record from a B-tree node, returning the file name it refers to
+func readDSRecord(node *dsReader) (string, error) {
+	length := int(node.uint32())
+	if node.err != nil || length > (len(node.data)-node.offset)/2 {
+		return "", fmt.Errorf(truncatedFileErr)
+	}
+	raw := node.read(length * 2)
+
+	units := make([]uint16, length)
+	for i := range units {
+		units[i] = binary.BigEndian.Uint16(raw[i*2:])
+	}
+
+	// structure ID (e.g. "Iloc"), followed by the data type
+	node.read(4)
+	dataType := string(node.read(4))
+
+	switch dataType {
+	case "bool":
+		node.read(1)
+	case "long", "shor", "type":
+		node.read(4)
+	case "comp", "dutc":
+		node.read(8)
+	case "blob":
+		node.read(int(node.uint32()))
+	case "ustr":
+		node.read(int(node.uint32()) * 2)
+	default:
+		if node.err == nil {
+			return "", fmt.Errorf(unknownRecordErr, dataType)
+		}
+	}
+
+	return string(utf16.Decode(units)), node.err
+}
+
+// Probes the directory for exposed version control repositories and .DS_Store files, and feeds the paths listed
+// in them to the frontier
+func (crawler *Crawler) probeVcs(dir url.URL) {
+	crawler.probeGit(dir)
+	crawler.probeSvn(dir)
+	crawler.probeHg(dir)
+	crawler.probeDSStore(dir)
+}
+
+func (crawler *Crawler) probeGit(dir url.URL) {
+	head, ok := crawler.fetchDirFile(dir, ".git/HEAD")
+	if !ok || !gitHeadRegex.Match(head) {
+		return
+	}
+
+	crawler.reportVcs(dir, ".git/")
+
+	if config, ok := crawler.fetchDirFile(dir, ".git/config"); ok {
+		for _, match := range gitRemoteRegex.FindAllSubmatch(config, -1) {
+			crawler.handleHostFinding(shared.Finding{
+				Type:   shared.VcsRemote,
+				Value:  string(match[1]),
+				Url:    resolveDirFile(dir, ".git/config"),
+				Source: shared.Vcs,
+			})
+		}
+	}
+
+	if index, ok := crawler.fetchDirFile(dir, ".git/index"); ok {
+		// truncated indexes still hold the paths read before the error
+		paths, _ := ParseGitIndex(index)
+		crawler.handleLeakedPaths(dir, ".git/index", paths)
+	}
+}
+
+func (crawler *Crawler) probeSvn(dir url.URL) {
+	if entries, ok := crawler.fetchDirFile(dir, ".svn/entries"); ok {
+		if paths, err := ParseSvnEntries(entries); err == nil {
+			crawler.reportVcs(dir, ".svn/")
+			crawler.handleLeakedPaths(dir, ".svn/entries", paths)
+			return
+		}
+	}
+
+	// working copies created by Subversion 1.7 or later keep their entries in a SQLite database
+	if db, ok := crawler.fetchDirFile(dir, ".svn/wc.db"); ok && bytes.HasPrefix(db, []byte("SQLite format 3\x00")) {
+		crawler.reportVcs(dir, ".svn/")
+	}
+}
+
+func (crawler *Crawler) probeHg(dir url.URL) {
+	requires, ok := crawler.fetchDirFile(dir, ".hg/requires")
+	if !ok || !bytes.Contains(requires, []byte("revlogv1")) {
+		return
+	}
+
+	crawler.reportVcs(dir, ".hg/")
+
+	if fncache, ok := crawler.fetchDirFile(dir, ".hg/store/fncache"); ok {
+		crawler.handleLeakedPaths(dir, ".hg/store/fncache", ParseHgFncache(fncache))
+	}
+}
+
+func (crawler *Crawler) probeDSStore(dir url.URL) {
+	data, ok := crawler.fetchDirFile(dir, ".DS_Store")
+	if !ok {
+		return
+	}
+
+	names, err := ParseDSStore(data)
+	if err != nil && len(names) == 0 {
+		return
+	}
+
+	file := resolveDirFile(dir, ".DS_Store")
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.ExposedDSStore,
+		Value:  file.String(),
+		Url:    file,
+		Source: shared.Vcs,
+	})
+
+	for _, name := range names {
+		// names without an extension are usually directories, which can hold their own .DS_Store
+		if !strings.Contains(name, ".") {
+			name += "/"
+		}
+
+		crawler.handleLeakedPaths(dir, ".DS_Store", []string{name})
+	}
+}
+
+func (crawler *Crawler) reportVcs(dir url.URL, repo string) {
+	repoUrl := resolveDirFile(dir, repo)
+	crawler.handleHostFinding(shared.Finding{
+		Type:   shared.ExposedVcs,
+		Value:  repoUrl.String(),
+		Url:    repoUrl,
+		Source: shared.Vcs,
+	})
+}
+
+// Reports the paths listed in a leaked file (relative to the directory) and feeds them to the frontier
+func (crawler *Crawler) handleLeakedPaths(dir url.URL, file string, paths []string) {
+	fileUrl := resolveDirFile(dir, file)
+
+	for _, path := range paths {
+		leaked := resolveDirFile(dir, path)
+		if leaked.Host != dir.Host {
+			continue
+		}
+
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.LeakedPath,
+			Value:  leaked.Path,
+			Url:    fileUrl,
+			Source: shared.Vcs,
+		})
+
+		crawler.handleFoundUrl(leaked.String(), dir.Host, dir.Scheme)
+	}
+}
+
+// Fetches a file relative to the directory, returning its body if the response was successful and not HTML
+func (crawler *Crawler) fetchDirFile(dir url.URL, file string) ([]byte, bool) {
+	status, header, body, err := crawler.probeRequest(http.MethodGet, resolveDirFile(dir, file), "", nil)
+	if err != nil || status != http.StatusOK || len(body) == 0 {
+		return nil, false
+	}
+
+	// none of the probed files are HTML, so these are usually catch-all pages
+	if strings.Contains(header.Get("Content-Type"), "text/html") {
+		return nil, false
+	}
+
+	return body, true
+}
+
+// Resolves a relative path against a directory, escaping characters that would be read as a query or fragment
+func resolveDirFile(dir url.URL, file string) url.URL {
+	ref := &url.URL{Path: strings.TrimPrefix(file, "/")}
+	return *dir.ResolveReference(ref)
+}
@@ -0,0 +1,203 @@
+package osint
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+	"unicode/utf16"
+)
+
+// Builds a git index with the given version, where each entry is stored with the previous path's suffix removed
+func buildGitIndex(version uint32, paths []string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("DIRC")
+	binary.Write(&buf, binary.BigEndian, version)
+	binary.Write(&buf, binary.BigEndian, uint32(len(paths)))
+
+	previous := ""
+	for _, path := range paths {
+		fixed := make([]byte, 62)
+		binary.BigEndian.PutUint16(fixed[60:], uint16(len(path)))
+		buf.Write(fixed)
+
+		if version == 4 {
+			common := 0
+			for common < len(previous) && common < len(path) && previous[common] == path[common] {
+				common++
+			}
+
+			buf.WriteByte(byte(len(previous) - common))
+			buf.WriteString(path[common:])
+			buf.WriteByte(0)
+		} else {
+			buf.WriteString(path)
+			padded := (62 + len(path) + 8) / 8 * 8
+			buf.Write(make([]byte, padded-62-len(path)))
+		}
+
+		previous = path
+	}
+
+	return buf.Bytes()
+}
+
+func TestParseGitIndex(t *testing.T) {
+	paths := []string{".env", "config/database.yml", "src/app.php", "src/lib.php"}
+
+	for _, version := range []uint32{2, 4} {
+		res, err := ParseGitIndex(buildGitIndex(version, paths))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(res, paths) {
+			t.Errorf("ParseGitIndex (version %d) expected %v; got %v", version, paths, res)
+		}
+	}
+
+	if _, err := ParseGitIndex([]byte("<html></html>")); err == nil {
+		t.Errorf("ParseGitIndex expected error for file without signature")
+	}
+
+	truncated := buildGitIndex(2, paths)
+	res, err := ParseGitIndex(truncated[:len(truncated)-20])
+	if err == nil || len(res) != 3 {
+		t.Errorf("ParseGitIndex expected 3 paths and an error for truncated index; got %v, %v", res, err)
+	}
+
+	// a removed length that overflows an int is rejected instead of slicing the previous path
+	overflow := buildGitIndex(4, []string{"a", "b"})
+	second := len(overflow) - 3
+	overflow = append(overflow[:second:second], bytes.Repeat([]byte{0xff}, 9)...)
+	overflow = append(overflow, 0x7f, 'b', 0)
+	res, err = ParseGitIndex(overflow)
+	if err == nil || len(res) != 1 {
+		t.Errorf("ParseGitIndex expected 1 path and an error for overflowing varint; got %v, %v", res, err)
+	}
+}
+
+type dsRecord struct {
+	name     string
+	dataType string
+	data     []byte
+}
+
+// Builds a .DS_Store with a single leaf node holding the records
+func buildDSStore(records []dsRecord) []byte {
+	buddy := make([]byte, 0x2000)
+	copy(buddy, "Bud1")
+	binary.BigEndian.PutUint32(buddy[4:], 0x800)
+	binary.BigEndian.PutUint32(buddy[8:], 0x800)
+	binary.BigEndian.PutUint32(buddy[12:], 0x800)
+
+	// the DSDB block is a 32 byte block at 0x1000, and the leaf is a 256 byte block at 0x1100
+	root := buddy[0x800:]
+	binary.BigEndian.PutUint32(root[0:], 2)
+	binary.BigEndian.PutUint32(root[8:], 0x1000|5)
+	binary.BigEndian.PutUint32(root[12:], 0x1100|8)
+
+	toc := root[8+256*4:]
+	binary.BigEndian.PutUint32(toc[0:], 1)
+	toc[4] = 4
+	copy(toc[5:], "DSDB")
+	binary.BigEndian.PutUint32(toc[9:], 0)
+
+	binary.BigEndian.PutUint32(buddy[0x1000:], 1)
+
+	var leaf bytes.Buffer
+	binary.Write(&leaf, binary.BigEndian, uint32(0))
+	binary.Write(&leaf, binary.BigEndian, uint32(len(records)))
+	for _, record := range records {
+		name := utf16.Encode([]rune(record.name))
+		binary.Write(&leaf, binary.BigEndian, uint32(len(name)))
+		binary.Write(&leaf, binary.BigEndian, name)
+		leaf.WriteString("Iloc")
+		leaf.WriteString(record.dataType)
+		leaf.Write(record.data)
+	}
+	copy(buddy[0x1100:], leaf.Bytes())
+
+	return append([]byte{0, 0, 0, 1}, buddy...)
+}
+
+func TestParseDSStore(t *testing.T) {
+	data := buildDSStore([]dsRecord{
+		{".", "bool", []byte{1}},
+		{"admin", "long", []byte{0, 0, 0, 1}},
+		{"backup.zip", "blob", []byte{0, 0, 0, 2, 0xff, 0xff}},
+		{"backup.zip", "ustr", []byte{0, 0, 0, 1, 0, 'a'}},
+		{"résumé.pdf", "comp", make([]byte, 8)},
+	})
+
+	expected := []string{"admin", "backup.zip", "résumé.pdf"}
+
+	res, err := ParseDSStore(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseDSStore expected %v; got %v", expected, res)
+	}
+
+	if _, err := ParseDSStore(data[:0x900]); err == nil {
+		t.Errorf("ParseDSStore expected error for truncated file")
+	}
+
+	if _, err := ParseDSStore([]byte("<html><body>Not found</body></html>")); err == nil {
+		t.Errorf("ParseDSStore expected error for file without signature")
+	}
+
+	// lengths that do not fit in the block are rejected before anything is allocated
+	hostile := buildDSStore([]dsRecord{{"admin", "blob", []byte{0xff, 0xff, 0xff, 0xff}}})
+	binary.BigEndian.PutUint32(hostile[4+0x1100+8:], 0xffffffff)
+	if _, err := ParseDSStore(hostile); err == nil || err.Error() != truncatedFileErr {
+		t.Errorf("ParseDSStore expected %q for record name longer than the block; got %v", truncatedFileErr, err)
+	}
+
+	res, err = ParseDSStore(buildDSStore([]dsRecord{{"admin", "ustr", []byte{0xff, 0xff, 0xff, 0xff}}}))
+	if err == nil || err.Error() != truncatedFileErr {
+		t.Errorf("ParseDSStore expected %q for string longer than the block; got %v, %v", truncatedFileErr, res, err)
+	}
+
+	// the leaf becomes an internal node whose child and rightmost child are the same block
+	reused := buildDSStore([]dsRecord{{"admin", "long", []byte{0, 0, 0, 1}}})
+	buddy := reused[4:]
+	binary.BigEndian.PutUint32(buddy[0x800:], 3)
+	binary.BigEndian.PutUint32(buddy[0x800+16:], 0x1200|8)
+	copy(buddy[0x1200:], buddy[0x1100:0x1200])
+
+	record := append([]byte{}, buddy[0x1108:0x1180]...)
+	binary.BigEndian.PutUint32(buddy[0x1100:], 2)
+	binary.BigEndian.PutUint32(buddy[0x1108:], 2)
+	copy(buddy[0x110c:], record)
+
+	if _, err := ParseDSStore(reused); err == nil || err.Error() != invalidDSStoreErr {
+		t.Errorf("ParseDSStore expected %q for block reached twice; got %v", invalidDSStoreErr, err)
+	}
+}
+
+func TestParseSvnEntries(t *testing.T) {
+	data := []byte("10\n\ndir\n12\nhttps://svn.example.com/trunk\n\f\nindex.php\nfile\n\n\n\f\nincludes\ndir\n\f\n")
+	expected := []string{"index.php", "includes/"}
+
+	res, err := ParseSvnEntries(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseSvnEntries expected %v; got %v", expected, res)
+	}
+}
+
+func TestParseHgFncache(t *testing.T) {
+	data := []byte("data/index.php.i\ndata/_r_e_a_d_m_e.md.i\ndata/my__file~3a.txt.i\ndata/lib.i.hg/util.py.i\ndata/big.bin.d\ndata/big.bin.i\n")
+	expected := []string{"index.php", "README.md", "my_file:.txt", "lib.i/util.py", "big.bin"}
+
+	res := ParseHgFncache(data)
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseHgFncache expected %v; got %v", expected, res)
+	}
+}
@@ -0,0 +1,359 @@
+package osint
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	emptyWellKnownErr = "file has no entries"
+	notOidcConfigErr  = "document is not an OpenID Connect configuration"
+)
+
+// Entry parsed from a well-known file. Lead holds an absolute URL or path to be fed back into discovery.
+type WellKnownEntry struct {
+	Name  string
+	Value string
+	Lead  string
+}
+
+type wellKnownFile struct {
+	path  string
+	parse func(data []byte) ([]WellKnownEntry, error)
+}
+
+var wellKnownFiles = []wellKnownFile{
+	{"/.well-known/security.txt", ParseSecurityTxt},
+	{"/security.txt", ParseSecurityTxt},
+	{"/.well-known/openid-configuration", ParseOpenIdConfiguration},
+	{"/.well-known/assetlinks.json", ParseAssetLinks},
+	{"/.well-known/apple-app-site-association", ParseAppleAppSiteAssociation},
+	{"/apple-app-site-association", ParseAppleAppSiteAssociation},
+	{"/humans.txt", ParseHumansTxt},
+	{"/crossdomain.xml", ParseCrossDomainXml},
+	{"/clientaccesspolicy.xml", ParseClientAccessPolicy},
+}
+
+// Parses the "Field: value" lines of a security.txt file (RFC 9116)
+func ParseSecurityTxt(data []byte) ([]WellKnownEntry, error) {
+	var entries []WellKnownEntry
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "-----") {
+			continue
+		}
+
+		name, value, found := strings.Cut(line, ":")
+		if !found || strings.ContainsAny(name, " \t") {
+			continue
+		}
+
+		value = strings.TrimSpace(value)
+		entries = append(entries, WellKnownEntry{Name: name, Value: value, Lead: urlLead(value)})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(emptyWellKnownErr)
+	}
+
+	return entries, nil
+}
+
+// Parses an OpenID Connect discovery document, returning every endpoint it advertises
+func ParseOpenIdConfiguration(data []byte) ([]WellKnownEntry, error) {
+	var config map[string]any
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+
+	if _, exists := config["issuer"]; !exists {
+		return nil, fmt.Errorf(notOidcConfigErr)
+	}
+
+	keys := make([]string, 0, len(config))
+	for key := range config {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var entries []WellKnownEntry
+	for _, key := range keys {
+		value, ok := config[key].(string)
+		if !ok || urlLead(value) == "" {
+			continue
+		}
+
+		entries = append(entries, WellKnownEntry{Name: key, Value: value, Lead: value})
+	}
+
+	return entries, nil
+}
+
+// Parses a Digital Asset Links file, returning the Android apps and websites associated with the host
+func ParseAssetLinks(data []byte) ([]WellKnownEntry, error) {
+	var statements []struct {
+		Target struct {
+			Namespace    string   `json:"namespace"`
+			PackageName  string   `json:"package_name"`
+			Fingerprints []string `json:"sha256_cert_fingerprints"`
+			Site         string   `json:"site"`
+		} `json:"target"`
+	}
+
+	if err := json.Unmarshal(data, &statements); err != nil {
+		return nil, err
+	}
+
+	var entries []WellKnownEntry
+	for _, statement := range statements {
+		switch statement.Target.Namespace {
+		case "android_app":
+			value := statement.Target.PackageName
+			if len(statement.Target.Fingerprints) > 0 {
+				value += " (" + strings.Join(statement.Target.Fingerprints, ", ") + ")"
+			}
+
+			entries = append(entries, WellKnownEntry{Name: "android_app", Value: value})
+		case "web":
+			entries = append(entries, WellKnownEntry{
+				Name:  "web",
+				Value: statement.Target.Site,
+				Lead:  urlLead(statement.Target.Site),
+			})
+		}
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(emptyWellKnownErr)
+	}
+
+	return entries, nil
+}
+
+// Parses an apple-app-site-association file, returning the iOS app IDs and the paths they handle
+func ParseAppleAppSiteAssociation(data []byte) ([]WellKnownEntry, error) {
+	type appList struct {
+		Apps []string `json:"apps"`
+	}
+
+	var association struct {
+		Applinks struct {
+			Details []struct {
+				AppID      string   `json:"appID"`
+				AppIDs     []string `json:"appIDs"`
+				Paths      []string `json:"paths"`
+				Components []struct {
+					Path string `json:"/"`
+				} `json:"components"`
+			} `json:"details"`
+		} `json:"applinks"`
+		WebCredentials appList `json:"webcredentials"`
+		AppClips       appList `json:"appclips"`
+	}
+
+	if err := json.Unmarshal(data, &association); err != nil {
+		return nil, err
+	}
+
+	var entries []WellKnownEntry
+	for _, detail := range association.Applinks.Details {
+		appIDs := detail.AppIDs
+		if detail.AppID != "" {
+			appIDs = append(appIDs, detail.AppID)
+		}
+
+		for _, appID := range appIDs {
+			entries = append(entries, WellKnownEntry{Name: "applinks", Value: appID})
+		}
+
+		paths := detail.Paths
+		for _, component := range detail.Components {
+			paths = append(paths, component.Path)
+		}
+
+		for _, path := range paths {
+			entries = append(entries, WellKnownEntry{Name: "path", Value: path, Lead: pathLead(path)})
+		}
+	}
+
+	for _, appID := range association.WebCredentials.Apps {
+		entries = append(entries, WellKnownEntry{Name: "webcredentials", Value: appID})
+	}
+
+	for _, appID := range association.AppClips.Apps {
+		entries = append(entries, WellKnownEntry{Name: "appclips", Value: appID})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(emptyWellKnownErr)
+	}
+
+	return entries, nil
+}
+
+// Returns the links mentioned in a humans.txt file
+func ParseHumansTxt(data []byte) ([]WellKnownEntry, error) {
+	var entries []WellKnownEntry
+	for _, link := range commentUrlRegex.FindAllString(string(data), -1) {
+		link = strings.TrimRight(link, ".,;:!")
+		entries = append(entries, WellKnownEntry{Name: "link", Value: link, Lead: link})
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf(emptyWellKnownErr)
+	}
+
+	return entries, nil
+}
+
+// Parses a Flash crossdomain.xml policy, returning the domains allowed to access the host
+func ParseCrossDomainXml(data []byte) ([]WellKnownEntry, error) {
+	type domainRule struct {
+		Domain string `xml:"domain,attr"`
+	}
+
+	var policy struct {
+		XMLName      xml.Name     `xml:"cross-domain-policy"`
+		AllowAccess  []domainRule `xml:"allow-access-from"`
+		AllowHeaders []domainRule `xml:"allow-http-request-headers-from"`
+	}
+
+	if err := xml.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+
+	var entries []WellKnownEntry
+	for _, rule := range policy.AllowAccess {
+		entries = append(entries, WellKnownEntry{Name: "allow-access-from", Value: rule.Domain, Lead: domainLead(rule.Domain)})
+	}
+
+	for _, rule := range policy.AllowHeaders {
+		entries = append(entries, WellKnownEntry{Name: "allow-http-request-headers-from", Value: rule.Domain, Lead: domainLead(rule.Domain)})
+	}
+
+	return entries, nil
+}
+
+// Parses a Silverlight clientaccesspolicy.xml, returning the origins allowed to access the host
+func ParseClientAccessPolicy(data []byte) ([]WellKnownEntry, error) {
+	var policy struct {
+		XMLName  xml.Name `xml:"access-policy"`
+		Policies []struct {
+			Domains []struct {
+				Uri string `xml:"uri,attr"`
+			} `xml:"allow-from>domain"`
+		} `xml:"cross-domain-access>policy"`
+	}
+
+	if err := xml.Unmarshal(data, &policy); err != nil {
+		return nil, err
+	}
+
+	var entries []WellKnownEntry
+	for _, p := range policy.Policies {
+		for _, domain := range p.Domains {
+			entries = append(entries, WellKnownEntry{Name: "allow-from", Value: domain.Uri, Lead: urlLead(domain.Uri)})
+		}
+	}
+
+	return entries, nil
+}
+
+// Returns the value if it is an absolute HTTP(S) URL
+func urlLead(value string) string {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return ""
+	}
+
+	return value
+}
+
+// Returns the value if it is an absolute path without wildcards or exclusions (e.g. "NOT /admin/*")
+func pathLead(value string) string {
+	if !strings.HasPrefix(value, "/") || strings.ContainsAny(value, "*? ") {
+		return ""
+	}
+
+	return value
+}
+
+// Converts a domain (which can have a leading wildcard) to a URL, ignoring the "*" wildcard
+func domainLead(domain string) string {
+	domain = strings.TrimPrefix(domain, "*.")
+	if domain == "" || strings.ContainsAny(domain, "*/ ") {
+		return ""
+	}
+
+	return "https://" + domain + "/"
+}
+
+// Checks if an entry allows any origin to access the host
+func isPermissiveEntry(entry WellKnownEntry) bool {
+	switch entry.Name {
+	case "allow-access-from", "allow-http-request-headers-from", "allow-from":
+		return entry.Value == "*" || entry.Value == "http://*" || entry.Value == "https://*"
+	}
+
+	return false
+}
+
+// Requests every well-known file on the host, reports their entries and feeds their URLs back into discovery
+func (crawler *Crawler) probeWellKnown(host url.URL) {
+	for _, file := range wellKnownFiles {
+		fileUrl := host
+		fileUrl.Path = file.path
+
+		status, header, body, err := crawler.probeRequest(http.MethodGet, fileUrl, "", nil)
+		if err != nil || status != http.StatusOK {
+			continue
+		}
+
+		// none of the files are HTML, so these are usually catch-all pages
+		if strings.Contains(header.Get("Content-Type"), "text/html") {
+			continue
+		}
+
+		entries, err := file.parse(body)
+		if err != nil {
+			continue
+		}
+
+		crawler.handleHostFinding(shared.Finding{
+			Type:   shared.WellKnownFile,
+			Value:  fileUrl.String(),
+			Url:    fileUrl,
+			Source: shared.WellKnown,
+		})
+
+		for _, entry := range entries {
+			findingType := shared.WellKnownEntry
+			if isPermissiveEntry(entry) {
+				findingType = shared.PermissiveCrossDomain
+			}
+
+			crawler.handleHostFinding(shared.Finding{
+				Type:   findingType,
+				Value:  entry.Name + ": " + entry.Value,
+				Url:    fileUrl,
+				Source: shared.WellKnown,
+			})
+
+			if entry.Lead != "" {
+				crawler.handleFoundUrl(entry.Lead, host.Host, host.Scheme)
+			}
+		}
+	}
+}
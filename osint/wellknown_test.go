@@ -0,0 +1,173 @@
+package osint
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSecurityTxt(t *testing.T) {
+	data := []byte(`-----BEGIN PGP SIGNED MESSAGE-----
+# Our security policy
+Contact: mailto:security@example.com
+Contact: https://example.com/report
+Expires: 2030-01-01T00:00:00.000Z
+Policy: https://example.com/security-policy
+`)
+
+	expected := []WellKnownEntry{
+		{Name: "Contact", Value: "mailto:security@example.com"},
+		{Name: "Contact", Value: "https://example.com/report", Lead: "https://example.com/report"},
+		{Name: "Expires", Value: "2030-01-01T00:00:00.000Z"},
+		{Name: "Policy", Value: "https://example.com/security-policy", Lead: "https://example.com/security-policy"},
+	}
+
+	res, err := ParseSecurityTxt(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseSecurityTxt expected %v; got %v", expected, res)
+	}
+
+	if _, err := ParseSecurityTxt([]byte("<html><body>Not found</body></html>")); err == nil {
+		t.Errorf("ParseSecurityTxt expected error for file without fields")
+	}
+}
+
+func TestParseOpenIdConfiguration(t *testing.T) {
+	data := []byte(`{
+		"issuer": "https://auth.example.com",
+		"token_endpoint": "https://auth.example.com/token",
+		"scopes_supported": ["openid"],
+		"response_types_supported": ["code"]
+	}`)
+
+	expected := []WellKnownEntry{
+		{Name: "issuer", Value: "https://auth.example.com", Lead: "https://auth.example.com"},
+		{Name: "token_endpoint", Value: "https://auth.example.com/token", Lead: "https://auth.example.com/token"},
+	}
+
+	res, err := ParseOpenIdConfiguration(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseOpenIdConfiguration expected %v; got %v", expected, res)
+	}
+
+	if _, err := ParseOpenIdConfiguration([]byte(`{"status": "ok"}`)); err == nil {
+		t.Errorf("ParseOpenIdConfiguration expected error for document without issuer")
+	}
+}
+
+func TestParseAssetLinks(t *testing.T) {
+	data := []byte(`[
+		{"relation": ["delegate_permission/common.handle_all_urls"],
+		 "target": {"namespace": "android_app", "package_name": "com.example.app", "sha256_cert_fingerprints": ["AA:BB"]}},
+		{"relation": ["delegate_permission/common.get_login_creds"],
+		 "target": {"namespace": "web", "site": "https://login.example.com"}}
+	]`)
+
+	expected := []WellKnownEntry{
+		{Name: "android_app", Value: "com.example.app (AA:BB)"},
+		{Name: "web", Value: "https://login.example.com", Lead: "https://login.example.com"},
+	}
+
+	res, err := ParseAssetLinks(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseAssetLinks expected %v; got %v", expected, res)
+	}
+}
+
+func TestParseAppleAppSiteAssociation(t *testing.T) {
+	data := []byte(`{
+		"applinks": {
+			"details": [
+				{"appID": "ABCDE12345.com.example.app", "paths": ["/orders/", "NOT /admin/*"]},
+				{"appIDs": ["ABCDE12345.com.example.other"], "components": [{"/": "/help"}]}
+			]
+		},
+		"webcredentials": {"apps": ["ABCDE12345.com.example.app"]}
+	}`)
+
+	expected := []WellKnownEntry{
+		{Name: "applinks", Value: "ABCDE12345.com.example.app"},
+		{Name: "path", Value: "/orders/", Lead: "/orders/"},
+		{Name: "path", Value: "NOT /admin/*"},
+		{Name: "applinks", Value: "ABCDE12345.com.example.other"},
+		{Name: "path", Value: "/help", Lead: "/help"},
+		{Name: "webcredentials", Value: "ABCDE12345.com.example.app"},
+	}
+
+	res, err := ParseAppleAppSiteAssociation(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseAppleAppSiteAssociation expected %v; got %v", expected, res)
+	}
+}
+
+func TestParseCrossDomainXml(t *testing.T) {
+	data := []byte(`<?xml version="1.0"?>
+<cross-domain-policy>
+	<allow-access-from domain="*.example.com" />
+	<allow-access-from domain="*" />
+	<allow-http-request-headers-from domain="cdn.example.net" headers="*" />
+</cross-domain-policy>`)
+
+	expected := []WellKnownEntry{
+		{Name: "allow-access-from", Value: "*.example.com", Lead: "https://example.com/"},
+		{Name: "allow-access-from", Value: "*"},
+		{Name: "allow-http-request-headers-from", Value: "cdn.example.net", Lead: "https://cdn.example.net/"},
+	}
+
+	res, err := ParseCrossDomainXml(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseCrossDomainXml expected %v; got %v", expected, res)
+	}
+
+	if !isPermissiveEntry(res[1]) || isPermissiveEntry(res[0]) {
+		t.Errorf("isPermissiveEntry expected only the \"*\" domain to be permissive")
+	}
+}
+
+func TestParseClientAccessPolicy(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<access-policy>
+	<cross-domain-access>
+		<policy>
+			<allow-from http-request-headers="*">
+				<domain uri="https://app.example.com" />
+				<domain uri="*" />
+			</allow-from>
+			<grant-to><resource path="/" include-subpaths="true" /></grant-to>
+		</policy>
+	</cross-domain-access>
+</access-policy>`)
+
+	expected := []WellKnownEntry{
+		{Name: "allow-from", Value: "https://app.example.com", Lead: "https://app.example.com"},
+		{Name: "allow-from", Value: "*"},
+	}
+
+	res, err := ParseClientAccessPolicy(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("ParseClientAccessPolicy expected %v; got %v", expected, res)
+	}
+}
@@ -0,0 +1,246 @@
+package osint
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"github.com/caio-ishikawa/netscout/shared"
+)
+
+// Errors
+const (
+	minimalNsecErr = "zone answers with minimally covering NSEC records, which cannot be walked"
+)
+
+// Denial-of-existence types of a signed zone
+const (
+	DenialNsec  = "NSEC"
+	DenialNsec3 = "NSEC3"
+)
+
+// Maximum amount of NSEC records followed in a single zone
+const maxWalkSteps = 10000
+
+// Random names queried to collect NSEC3 hashes, and the amount of consecutive queries without new hashes after
+// which the collection stops
+const (
+	maxNsec3Queries          = 1000
+	maxQueriesWithoutNewHash = 100
+)
+
+// Names of a zone enumerated through its denial-of-existence records. For NSEC3 zones, Hashes holds every hash
+// collected and Names the ones that were cracked.
+type ZoneWalkResult struct {
+	Denial   string
+	Names    []string
+	Hashes   []string
+	Params   Nsec3Params
+	Complete bool
+}
+
+// Parameters the NSEC3 hashes of a zone were computed with
+type Nsec3Params struct {
+	Algorithm  uint8
+	Iterations uint16
+	Salt       string
+}
+
+// Enumerates the names of a DNSSEC-signed zone without a zone transfer. NSEC zones are walked by following the
+// chain of next names. For NSEC3 zones, the hashes of the chain are collected by querying random names and cracked
+// with the words (e.g. "www" for www.example.com). Unsigned zones return an empty result.
+func (resolver *SubdomainResolver) WalkZone(domain string, words []string) (ZoneWalkResult, error) {
+	apex := dns.Fqdn(strings.ToLower(domain))
+
+	var result ZoneWalkResult
+	var err error
+
+	result.Denial, err = resolver.detectDenial(apex)
+	if err != nil {
+		return result, err
+	}
+
+	switch result.Denial {
+	case DenialNsec:
+		result.Names, result.Complete, err = resolver.walkNsec(apex)
+	case DenialNsec3:
+		var hashes map[string]string
+		hashes, result.Params, result.Complete = resolver.collectNsec3(apex)
+
+		for hash := range hashes {
+			result.Hashes = append(result.Hashes, hash)
+		}
+		sort.Strings(result.Hashes)
+
+		result.Names = CrackNsec3(result.Hashes, result.Params, apex, words)
+	}
+
+	return result, err
+}
+
+// Returns the zone's denial-of-existence type, read from the records that prove a random name does not exist
+func (resolver *SubdomainResolver) detectDenial(apex string) (string, error) {
+	res, err := resolver.queryDnssec(randomPath()+"."+apex, dns.TypeA)
+	if err != nil {
+		return "", err
+	}
+
+	for _, rr := range res.Ns {
+		switch rr.(type) {
+		case *dns.NSEC:
+			return DenialNsec, nil
+		case *dns.NSEC3:
+			return DenialNsec3, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Follows the NSEC chain from the apex until it wraps around, returning every name found under the apex
+func (resolver *SubdomainResolver) walkNsec(apex string) ([]string, bool, error) {
+	var names []string
+	visited := map[string]struct{}{apex: {}}
+
+	current := apex
+	for step := 0; step < maxWalkSteps; step++ {
+		next, err := resolver.nextNsecName(current)
+		if err != nil {
+			return names, false, err
+		}
+
+		if strings.HasPrefix(next, "\\000.") {
+			return names, false, fmt.Errorf(minimalNsecErr)
+		}
+
+		if _, exists := visited[next]; exists || !dns.IsSubDomain(apex, next) {
+			return names, true, nil
+		}
+		visited[next] = struct{}{}
+
+		if !strings.HasPrefix(next, "*.") {
+			names = append(names, strings.TrimSuffix(next, "."))
+		}
+
+		current = next
+	}
+
+	return names, false, nil
+}
+
+// Returns the name that follows the name in the zone's NSEC chain. The NSEC record of the name is requested first,
+// and the one proving that the name's first possible child does not exist is used if it is not returned.
+func (resolver *SubdomainResolver) nextNsecName(name string) (string, error) {
+	for _, qname := range []string{name, "\\000." + name} {
+		qtype := dns.TypeNSEC
+		if qname != name {
+			qtype = dns.TypeA
+		}
+
+		res, err := resolver.queryDnssec(qname, qtype)
+		if err != nil {
+			return "", err
+		}
+
+		for _, rr := range append(res.Answer, res.Ns...) {
+			if nsec, ok := rr.(*dns.NSEC); ok && strings.EqualFold(nsec.Hdr.Name, name) {
+				return strings.ToLower(nsec.NextDomain), nil
+			}
+		}
+	}
+
+	return "", fmt.Errorf(unexpectedResponseErr)
+}
+
+// Collects the NSEC3 hashes of the zone by querying random names, until the hashes form a complete chain or no new
+// ones are found. Returns every hash mapped to the next one in the chain.
+func (resolver *SubdomainResolver) collectNsec3(apex string) (map[string]string, Nsec3Params, bool) {
+	hashes := map[string]string{}
+	var params Nsec3Params
+
+	withoutNew := 0
+	for query := 0; query < maxNsec3Queries && withoutNew < maxQueriesWithoutNewHash; query++ {
+		res, err := resolver.queryDnssec(randomPath()+"."+apex, dns.TypeA)
+		if err != nil {
+			withoutNew++
+			continue
+		}
+
+		found := false
+		for _, rr := range res.Ns {
+			nsec3, ok := rr.(*dns.NSEC3)
+			if !ok {
+				continue
+			}
+
+			params = Nsec3Params{Algorithm: nsec3.Hash, Iterations: nsec3.Iterations, Salt: strings.ToUpper(nsec3.Salt)}
+
+			hash := strings.ToUpper(dns.SplitDomainName(nsec3.Hdr.Name)[0])
+			if _, exists := hashes[hash]; !exists {
+				hashes[hash] = strings.ToUpper(nsec3.NextDomain)
+				found = true
+			}
+		}
+
+		if !found {
+			withoutNew++
+			continue
+		}
+		withoutNew = 0
+
+		if nsec3ChainComplete(hashes) {
+			return hashes, params, true
+		}
+	}
+
+	return hashes, params, nsec3ChainComplete(hashes)
+}
+
+// Checks if the next hash of every hash is also known, which means every hash of the zone was collected
+func nsec3ChainComplete(hashes map[string]string) bool {
+	if len(hashes) == 0 {
+		return false
+	}
+
+	for _, next := range hashes {
+		if _, exists := hashes[next]; !exists {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Hashes every word as a label of the apex, returning the names whose hashes were collected
+func CrackNsec3(hashes []string, params Nsec3Params, apex string, words []string) []string {
+	collected := map[string]struct{}{}
+	for _, hash := range hashes {
+		collected[strings.ToUpper(hash)] = struct{}{}
+	}
+
+	var names []string
+	for _, word := range words {
+		name := dns.Fqdn(strings.ToLower(strings.Trim(word, ".")) + "." + strings.TrimSuffix(apex, "."))
+		if _, exists := collected[dns.HashName(name, params.Algorithm, params.Iterations, params.Salt)]; !exists {
+			continue
+		}
+
+		if trimmed := strings.TrimSuffix(name, "."); !shared.SliceContains(names, trimmed) {
+			names = append(names, trimmed)
+		}
+	}
+
+	return names
+}
+
+// Sends a query with the DNSSEC OK bit set, so denial-of-existence records are returned
+func (resolver *SubdomainResolver) queryDnssec(name string, qtype uint16) (*dns.Msg, error) {
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(4096, true)
+
+	res, _, err := resolver.exchange(msg)
+	return res, err
+}
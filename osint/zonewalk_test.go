@@ -0,0 +1,148 @@
+package osint
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// Names of the test zone in canonical order
+var walkedZone = []string{"example.com.", "_dmarc.example.com.", "api.example.com.", "mail.example.com.", "www.example.com."}
+
+func TestWalkZoneNsec(t *testing.T) {
+	nsec := func(i int) dns.RR {
+		return &dns.NSEC{
+			Hdr:        dns.RR_Header{Name: walkedZone[i], Rrtype: dns.TypeNSEC, Class: dns.ClassINET, Ttl: 300},
+			NextDomain: walkedZone[(i+1)%len(walkedZone)],
+		}
+	}
+
+	// the NSEC record of the last name before the query proves it does not exist
+	covering := func(name string) dns.RR {
+		label := dns.SplitDomainName(name)[0]
+		for i := len(walkedZone) - 1; i > 0; i-- {
+			if strings.Compare(dns.SplitDomainName(walkedZone[i])[0], label) < 0 {
+				return nsec(i)
+			}
+		}
+
+		return nsec(0)
+	}
+
+	addr := serveDns(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		res := new(dns.Msg)
+		res.SetReply(req)
+
+		question := req.Question[0]
+		for i, name := range walkedZone {
+			if name == question.Name {
+				if question.Qtype == dns.TypeNSEC {
+					res.Answer = append(res.Answer, nsec(i))
+				} else {
+					res.Ns = append(res.Ns, nsec(i))
+				}
+
+				w.WriteMsg(res)
+				return
+			}
+		}
+
+		res.Rcode = dns.RcodeNameError
+		res.Ns = append(res.Ns, covering(question.Name))
+		w.WriteMsg(res)
+	}))
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := resolver.WalkZone("example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ZoneWalkResult{
+		Denial:   DenialNsec,
+		Names:    []string{"_dmarc.example.com", "api.example.com", "mail.example.com", "www.example.com"},
+		Complete: true,
+	}
+
+	if !reflect.DeepEqual(res, expected) {
+		t.Errorf("WalkZone expected %+v; got %+v", expected, res)
+	}
+}
+
+func TestWalkZoneNsec3(t *testing.T) {
+	params := Nsec3Params{Algorithm: dns.SHA1, Iterations: 2, Salt: "AABBCCDD"}
+
+	var hashes []string
+	for _, name := range walkedZone {
+		hashes = append(hashes, dns.HashName(name, params.Algorithm, params.Iterations, params.Salt))
+	}
+	sort.Strings(hashes)
+
+	var records []*dns.NSEC3
+	for i, hash := range hashes {
+		records = append(records, &dns.NSEC3{
+			Hdr:        dns.RR_Header{Name: strings.ToLower(hash) + ".example.com.", Rrtype: dns.TypeNSEC3, Class: dns.ClassINET, Ttl: 300},
+			Hash:       params.Algorithm,
+			Iterations: params.Iterations,
+			SaltLength: uint8(len(params.Salt) / 2),
+			Salt:       params.Salt,
+			HashLength: 20,
+			NextDomain: hashes[(i+1)%len(hashes)],
+		})
+	}
+
+	addr := serveDns(t, dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		res := new(dns.Msg)
+		res.SetReply(req)
+		res.Rcode = dns.RcodeNameError
+
+		for _, record := range records {
+			if record.Cover(req.Question[0].Name) {
+				res.Ns = append(res.Ns, record)
+			}
+		}
+
+		w.WriteMsg(res)
+	}))
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := resolver.WalkZone("example.com", []string{"www", "api", "dev", "mail"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if res.Denial != DenialNsec3 || !res.Complete || res.Params != params || !reflect.DeepEqual(res.Hashes, hashes) {
+		t.Errorf("WalkZone expected the complete NSEC3 chain %v; got %+v", hashes, res)
+	}
+
+	expected := []string{"www.example.com", "api.example.com", "mail.example.com"}
+	if !reflect.DeepEqual(res.Names, expected) {
+		t.Errorf("WalkZone expected cracked names %v; got %v", expected, res.Names)
+	}
+}
+
+func TestWalkZoneUnsigned(t *testing.T) {
+	addr := startStubResolver(t, "www.example.com. 300 IN A 192.0.2.1")
+
+	resolver, err := NewSubdomainResolver([]string{addr}, 0, 1, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := resolver.WalkZone("example.com", []string{"www"})
+	if err != nil || res.Denial != "" || len(res.Names) != 0 {
+		t.Errorf("WalkZone expected empty result for unsigned zone; got %+v, %v", res, err)
+	}
+}
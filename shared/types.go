@@ -8,11 +8,97 @@ import (
 type Source string
 
 const (
-	Axfr         Source = "DNS_AXFR"
-	Crawler      Source = "CRAWLER"
-	BinaryEdge   Source = "BINARY_EDGE"
-	Serp         Source = "SERP"
-	ShortenedUrl Source = "SHORTENED_URL"
+	Axfr             Source = "DNS_AXFR"
+	Crawler          Source = "CRAWLER"
+	BinaryEdge       Source = "BINARY_EDGE"
+	Serp             Source = "SERP"
+	ShortenedUrl     Source = "SHORTENED_URL"
+	Contacts         Source = "CONTACTS"
+	Comments         Source = "COMMENTS"
+	Fingerprint      Source = "FINGERPRINT"
+	Favicon          Source = "FAVICON"
+	HeaderAudit      Source = "HEADER_AUDIT"
+	SourceMaps       Source = "SOURCE_MAPS"
+	ApiDiscovery     Source = "API_DISCOVERY"
+	Realtime         Source = "REALTIME"
+	WellKnown        Source = "WELL_KNOWN"
+	Vcs              Source = "VCS"
+	ContentDiscovery Source = "CONTENT_DISCOVERY"
+	Soft404          Source = "SOFT_404"
+	Backups          Source = "BACKUPS"
+	DirListing       Source = "DIR_LISTING"
+	Documents        Source = "DOCUMENTS"
+	DnsBruteForce    Source = "DNS_BRUTE_FORCE"
+	Permutations     Source = "PERMUTATIONS"
+	DnsRecords       Source = "DNS_RECORDS"
+	Takeover         Source = "TAKEOVER"
+	ZoneWalk         Source = "DNS_ZONE_WALK"
+	MailRecords      Source = "MAIL_RECORDS"
+	ReverseDns       Source = "REVERSE_DNS"
+)
+
+// Describes what a Finding represents
+type FindingType string
+
+const (
+	EmailOnDomain  FindingType = "EMAIL_ON_DOMAIN"
+	EmailOffDomain FindingType = "EMAIL_OFF_DOMAIN"
+	Phone          FindingType = "PHONE"
+	SocialProfile  FindingType = "SOCIAL_PROFILE"
+	Comment        FindingType = "COMMENT"
+	Technology     FindingType = "TECHNOLOGY"
+	FaviconHash    FindingType = "FAVICON_HASH"
+	MissingHeader  FindingType = "MISSING_SECURITY_HEADER"
+	WeakHeader     FindingType = "WEAK_SECURITY_HEADER"
+	PermissiveCors FindingType = "PERMISSIVE_CORS"
+	InsecureCookie FindingType = "INSECURE_COOKIE"
+	SourceMapFile  FindingType = "SOURCE_MAP"
+	OriginalSource FindingType = "ORIGINAL_SOURCE"
+
+	ApiSpec          FindingType = "API_SPEC"
+	ApiOperation     FindingType = "API_OPERATION"
+	GraphQLEndpoint  FindingType = "GRAPHQL_ENDPOINT"
+	GraphQLOperation FindingType = "GRAPHQL_OPERATION"
+	GraphQLType      FindingType = "GRAPHQL_TYPE"
+
+	WebSocketEndpoint   FindingType = "WEBSOCKET_ENDPOINT"
+	EventSourceEndpoint FindingType = "EVENTSOURCE_ENDPOINT"
+	RealtimeCallSite    FindingType = "REALTIME_CALL_SITE"
+
+	WellKnownFile         FindingType = "WELL_KNOWN_FILE"
+	WellKnownEntry        FindingType = "WELL_KNOWN_ENTRY"
+	PermissiveCrossDomain FindingType = "PERMISSIVE_CROSS_DOMAIN"
+
+	ExposedVcs     FindingType = "EXPOSED_VCS"
+	ExposedDSStore FindingType = "EXPOSED_DS_STORE"
+	VcsRemote      FindingType = "VCS_REMOTE"
+	LeakedPath     FindingType = "LEAKED_PATH"
+
+	DiscoveredContent FindingType = "DISCOVERED_CONTENT"
+	ProbableSoft404   FindingType = "PROBABLE_SOFT_404"
+	BackupFile        FindingType = "BACKUP_FILE"
+	DirectoryListing  FindingType = "DIRECTORY_LISTING"
+	ListedFile        FindingType = "LISTED_FILE"
+
+	ZoneTransferLeak  FindingType = "ZONE_TRANSFER_LEAK"
+	ResolvedSubdomain FindingType = "RESOLVED_SUBDOMAIN"
+	DnsRecord         FindingType = "DNS_RECORD"
+	RelatedHost       FindingType = "RELATED_HOST"
+	PtrRecord         FindingType = "PTR_RECORD"
+
+	MailIpRange        FindingType = "MAIL_IP_RANGE"
+	DmarcReportAddress FindingType = "DMARC_REPORT_ADDRESS"
+
+	SubdomainTakeover       FindingType = "SUBDOMAIN_TAKEOVER"
+	LikelySubdomainTakeover FindingType = "LIKELY_SUBDOMAIN_TAKEOVER"
+
+	DocumentUrl      FindingType = "DOCUMENT_URL"
+	DocumentAuthor   FindingType = "DOCUMENT_AUTHOR"
+	DocumentSoftware FindingType = "DOCUMENT_SOFTWARE"
+	DocumentUsername FindingType = "DOCUMENT_USERNAME"
+	DocumentPath     FindingType = "DOCUMENT_PATH"
+	ImageCamera      FindingType = "IMAGE_CAMERA"
+	ImageLocation    FindingType = "IMAGE_LOCATION"
 )
 
 type ScannedItem struct {
@@ -24,8 +110,31 @@ func (si *ScannedItem) Format() string {
 	return fmt.Sprintf("[%s] %s\n", si.Source, si.Url.String())
 }
 
+// Represents a non-URL entity found during the scan, along with the URL where it was found
+type Finding struct {
+	Type   FindingType
+	Value  string
+	Url    url.URL
+	Source Source
+}
+
+// Checks if the finding is likely to expose source code or sensitive data
+func (f *Finding) IsHighInterest() bool {
+	switch f.Type {
+	case BackupFile, ExposedVcs, ExposedDSStore, ZoneTransferLeak, SubdomainTakeover, LikelySubdomainTakeover:
+		return true
+	}
+
+	return false
+}
+
+func (f *Finding) Format() string {
+	return fmt.Sprintf("[%s] %s %s (%s)\n", f.Source, f.Type, f.Value, f.Url.String())
+}
+
 type CommsChannels struct {
 	DataChan          chan ScannedItem
+	FindingChan       chan Finding
 	WarningChan       chan string
 	CrawlDoneChan     chan struct{}
 	ShortenedDoneChan chan struct{}
@@ -34,6 +143,7 @@ type CommsChannels struct {
 func NewCommsChannels() CommsChannels {
 	return CommsChannels{
 		DataChan:          make(chan ScannedItem),
+		FindingChan:       make(chan Finding),
 		WarningChan:       make(chan string),
 		CrawlDoneChan:     make(chan struct{}),
 		ShortenedDoneChan: make(chan struct{}),
@@ -1,8 +1,11 @@
 package shared
 
 import (
+	"net"
 	"net/url"
 	"strings"
+
+	"golang.org/x/net/publicsuffix"
 )
 
 // Checks if string exists in a slice
@@ -16,6 +19,16 @@ func SliceContains(slice []string, toCheck string) bool {
 	return false
 }
 
+func SliceContainsInt(slice []int, toCheck int) bool {
+	for _, x := range slice {
+		if x == toCheck {
+			return true
+		}
+	}
+
+	return false
+}
+
 func SliceContainsURL(slice []url.URL, toCheck url.URL) bool {
 	for _, x := range slice {
 		if x == toCheck {
@@ -32,3 +45,23 @@ func RemoveScheme(targetUrl url.URL) string {
 
 	return strings.Replace(filteredScheme, "/", "", 1)
 }
+
+// Returns the registrable domain (eTLD+1) of a host, or the host itself if it cannot be determined
+func BaseDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return host
+	}
+
+	return domain
+}
+
+// Checks if both hosts share the same registrable domain (e.g. www.example.com and api.example.com)
+func SameBaseDomain(host string, other string) bool {
+	return BaseDomain(host) == BaseDomain(other)
+}
@@ -24,3 +24,24 @@ func TestRemoveScheme(t *testing.T) {
 		}
 	}
 }
+
+func TestSameBaseDomain(t *testing.T) {
+	cases := []struct {
+		host     string
+		other    string
+		expected bool
+	}{
+		{"www.example.com", "example.com", true},
+		{"api.example.com:8080", "mail.example.com", true},
+		{"example.co.uk", "shop.example.co.uk", true},
+		{"example.com", "example.org", false},
+		{"evil-example.com", "example.com", false},
+	}
+
+	for _, tc := range cases {
+		res := SameBaseDomain(tc.host, tc.other)
+		if res != tc.expected {
+			t.Errorf("SameBaseDomain(%s, %s) expected %v; got %v", tc.host, tc.other, tc.expected, res)
+		}
+	}
+}